@@ -0,0 +1,67 @@
+package go_dynamodb_wrapper
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/applicationautoscaling"
+	aastypes "github.com/aws/aws-sdk-go-v2/service/applicationautoscaling/types"
+)
+
+// ConfigureAutoScaling registers the read and write capacity of a provisioned
+// table with Application Auto Scaling, keeping each between minCapacity and
+// maxCapacity with a target tracking policy aiming at targetUtilization percent
+// (e.g. 70).
+func (ddb *DDBTable) ConfigureAutoScaling(ctx context.Context, minCapacity, maxCapacity int64, targetUtilization float64) error {
+	if minCapacity < 1 || maxCapacity < minCapacity || maxCapacity > math.MaxInt32 {
+		return fmt.Errorf("invalid capacity range: %d-%d", minCapacity, maxCapacity)
+	}
+	if targetUtilization < 20 || targetUtilization > 90 {
+		return fmt.Errorf("target utilization must be between 20 and 90 percent, got %v", targetUtilization)
+	}
+
+	client := applicationautoscaling.NewFromConfig(ddb.awsConfig)
+	resourceID := "table/" + ddb.name
+
+	dimensions := []struct {
+		dimension aastypes.ScalableDimension
+		metric    aastypes.MetricType
+	}{
+		{aastypes.ScalableDimensionDynamoDBTableReadCapacityUnits, aastypes.MetricTypeDynamoDBReadCapacityUtilization},
+		{aastypes.ScalableDimensionDynamoDBTableWriteCapacityUnits, aastypes.MetricTypeDynamoDBWriteCapacityUtilization},
+	}
+
+	for _, d := range dimensions {
+		_, err := client.RegisterScalableTarget(ctx, &applicationautoscaling.RegisterScalableTargetInput{
+			ServiceNamespace:  aastypes.ServiceNamespaceDynamodb,
+			ResourceId:        aws.String(resourceID),
+			ScalableDimension: d.dimension,
+			MinCapacity:       aws.Int32(int32(minCapacity)),
+			MaxCapacity:       aws.Int32(int32(maxCapacity)),
+		})
+		if err != nil {
+			return ddb.wrapError("RegisterScalableTarget", fmt.Errorf("%s: %w", d.dimension, err))
+		}
+
+		_, err = client.PutScalingPolicy(ctx, &applicationautoscaling.PutScalingPolicyInput{
+			PolicyName:        aws.String(fmt.Sprintf("%s-%s", ddb.name, d.metric)),
+			ServiceNamespace:  aastypes.ServiceNamespaceDynamodb,
+			ResourceId:        aws.String(resourceID),
+			ScalableDimension: d.dimension,
+			PolicyType:        aastypes.PolicyTypeTargetTrackingScaling,
+			TargetTrackingScalingPolicyConfiguration: &aastypes.TargetTrackingScalingPolicyConfiguration{
+				TargetValue: aws.Float64(targetUtilization),
+				PredefinedMetricSpecification: &aastypes.PredefinedMetricSpecification{
+					PredefinedMetricType: d.metric,
+				},
+			},
+		})
+		if err != nil {
+			return ddb.wrapError("PutScalingPolicy", fmt.Errorf("%s: %w", d.metric, err))
+		}
+	}
+
+	return nil
+}
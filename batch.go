@@ -0,0 +1,407 @@
+package go_dynamodb_wrapper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	batchGetMaxKeys     = 100
+	batchWriteMaxItems  = 25
+	batchMaxAttempts    = 5
+	batchInitialBackoff = 50 * time.Millisecond
+)
+
+// BatchStats sums up what a batch operation cost.
+type BatchStats struct {
+	// Requests is the number of API requests made, retries included.
+	Requests int
+	// CapacityUnits is the capacity consumed on the table by all of them.
+	CapacityUnits float64
+}
+
+// BatchGetItems reads the items with the given partition keys. Missing items are
+// simply absent from the result, which is in no particular order. Keys that
+// DynamoDB still leaves unprocessed after retrying are reported with an
+// *UnprocessedKeysError, returned along with the items that could be read.
+func (ddb *DDBTable) BatchGetItems(ctx context.Context, partitionKeyValues []string, opts ...BatchGetOption) ([]map[string]interface{}, error) {
+	return ddb.batchGetItems(ctx, partitionKeyValues, newBatchGetOptions(opts), nil)
+}
+
+// BatchGetItemsWithStats works like BatchGetItems and also returns the consumed
+// read capacity.
+func (ddb *DDBTable) BatchGetItemsWithStats(ctx context.Context, partitionKeyValues []string, opts ...BatchGetOption) ([]map[string]interface{}, BatchStats, error) {
+	var stats BatchStats
+	items, err := ddb.batchGetItems(ctx, partitionKeyValues, newBatchGetOptions(opts), &stats)
+	return items, stats, err
+}
+
+// ExistsBatch reports which of the given partition keys exist, only fetching the
+// key attribute of each item. Keys left unprocessed are missing from the result,
+// which is then returned along with an *UnprocessedKeysError.
+func (ddb *DDBTable) ExistsBatch(ctx context.Context, partitionKeyValues []string) (map[string]bool, error) {
+	items, err := ddb.batchGetItems(ctx, partitionKeyValues, newBatchGetOptions([]BatchGetOption{WithBatchProjection(ddb.partitionKeyName)}), nil)
+
+	var unprocessed *UnprocessedKeysError
+	if err != nil && !errors.As(err, &unprocessed) {
+		return nil, err
+	}
+
+	exists := make(map[string]bool, len(partitionKeyValues))
+	for _, pk := range partitionKeyValues {
+		exists[pk] = false
+	}
+	if unprocessed != nil {
+		for _, pk := range unprocessed.PartitionKeyValues {
+			delete(exists, pk)
+		}
+	}
+	for _, item := range items {
+		pk, keyErr := ddb.Key(item)
+		if keyErr != nil {
+			return nil, keyErr
+		}
+		exists[pk] = true
+	}
+
+	return exists, err
+}
+
+// ReadItemsParallel reads the items with the given partition keys using up to
+// concurrency concurrent GetItem calls. The result is aligned with
+// partitionKeyValues, holding nil for missing items. A failing read doesn't stop
+// the others: the items that could be read are returned along with an error
+// joining the failures.
+func (ddb *DDBTable) ReadItemsParallel(ctx context.Context, partitionKeyValues []string, concurrency int) ([]map[string]interface{}, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	items := make([]map[string]interface{}, len(partitionKeyValues))
+	errs := make([]error, len(partitionKeyValues))
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, pk := range partitionKeyValues {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, pk string) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			item, err := ddb.ReadItemRaw(ctx, pk)
+			switch {
+			case errors.Is(err, ErrItemNotFound):
+			case err != nil:
+				errs[i] = fmt.Errorf("key %q: %w", pk, err)
+			default:
+				items[i] = ddb.convertItem(item)
+			}
+		}(i, pk)
+	}
+	wg.Wait()
+
+	return items, errors.Join(errs...)
+}
+
+// BatchWriteItems writes the items in as few BatchWriteItem requests as possible,
+// 25 items per request. Items are at most 400KB, so a full request stays well
+// within the 16MB request size limit and only the item count needs watching.
+// Items DynamoDB leaves unprocessed are retried with exponential backoff.
+func (ddb *DDBTable) BatchWriteItems(ctx context.Context, items []map[string]interface{}) error {
+	return ddb.batchWriteItems(ctx, items, nil)
+}
+
+// BatchWriteItemsWithStats works like BatchWriteItems and also returns the
+// consumed write capacity.
+func (ddb *DDBTable) BatchWriteItemsWithStats(ctx context.Context, items []map[string]interface{}) (BatchStats, error) {
+	var stats BatchStats
+	err := ddb.batchWriteItems(ctx, items, &stats)
+	return stats, err
+}
+
+// BatchWriter buffers writes and sends them 25 at a time with BatchWriteItem,
+// retrying unprocessed items. Call Close once done to write what is left. A
+// BatchWriter is not safe for concurrent use.
+type BatchWriter struct {
+	table   *DDBTable
+	pending []types.WriteRequest
+}
+
+// NewBatchWriter returns a BatchWriter writing to table.
+func NewBatchWriter(table *DDBTable) *BatchWriter {
+	return &BatchWriter{table: table}
+}
+
+// Add buffers item, flushing the buffer once it holds a full batch.
+func (w *BatchWriter) Add(ctx context.Context, item map[string]interface{}) error {
+	dynamodbItem, err := w.table.marshalMap(item)
+	if err != nil {
+		return err
+	}
+	if err := w.table.prepareItem(dynamodbItem); err != nil {
+		return err
+	}
+
+	w.pending = append(w.pending, types.WriteRequest{PutRequest: &types.PutRequest{Item: dynamodbItem}})
+	if len(w.pending) >= batchWriteMaxItems {
+		return w.Flush(ctx)
+	}
+
+	return nil
+}
+
+// Flush writes the buffered items, 25 per request. On failure the items not
+// written yet stay buffered, so that a later Flush retries them.
+func (w *BatchWriter) Flush(ctx context.Context) error {
+	for len(w.pending) > 0 {
+		n := min(len(w.pending), batchWriteMaxItems)
+		if err := w.table.batchWriteChunk(ctx, w.pending[:n], nil); err != nil {
+			return err
+		}
+		w.pending = w.pending[n:]
+	}
+	w.pending = nil
+
+	return nil
+}
+
+// Close flushes the remaining items.
+func (w *BatchWriter) Close(ctx context.Context) error {
+	return w.Flush(ctx)
+}
+
+// DeleteWhere deletes every item matching filterExpression, whose #name and
+// :value placeholders are filled from names and values. It scans the whole
+// table, so its cost depends on the table size rather than the number of
+// matches. It returns how many items were deleted.
+func (ddb *DDBTable) DeleteWhere(ctx context.Context, filterExpression string, names map[string]string, values map[string]interface{}) (int, error) {
+	input := &dynamodb.ScanInput{
+		TableName:        aws.String(ddb.name),
+		ConsistentRead:   ddb.consistentRead(nil),
+		FilterExpression: aws.String(filterExpression),
+	}
+	if len(names) > 0 {
+		input.ExpressionAttributeNames = names
+	}
+	if len(values) > 0 {
+		expressionAttributeValues, err := ddb.marshalMap(values)
+		if err != nil {
+			return 0, err
+		}
+		input.ExpressionAttributeValues = expressionAttributeValues
+	}
+
+	deleted := 0
+	pages := NewPaginator(ddb.scanPages(input), rawPageItem)
+	for pages.HasMore() {
+		items, err := pages.NextPage(ctx)
+		if err != nil {
+			return deleted, err
+		}
+
+		for start := 0; start < len(items); start += batchWriteMaxItems {
+			end := min(start+batchWriteMaxItems, len(items))
+
+			requests := make([]types.WriteRequest, 0, end-start)
+			for _, item := range items[start:end] {
+				requests = append(requests, types.WriteRequest{DeleteRequest: &types.DeleteRequest{Key: ddb.itemKey(item)}})
+			}
+			if err := ddb.batchWriteChunk(ctx, requests, nil); err != nil {
+				return deleted, err
+			}
+			deleted += len(requests)
+		}
+	}
+
+	return deleted, nil
+}
+
+////////////////////////
+// Internal functions //
+////////////////////////
+
+func (ddb *DDBTable) batchGetItems(ctx context.Context, partitionKeyValues []string, options *batchGetOptions, stats *BatchStats) ([]map[string]interface{}, error) {
+	keys, err := ddb.uniqueKeys(partitionKeyValues)
+	if err != nil {
+		return nil, err
+	}
+
+	var returnedList []map[string]interface{}
+	var unprocessed []string
+	for start := 0; start < len(keys); start += batchGetMaxKeys {
+		end := min(start+batchGetMaxKeys, len(keys))
+
+		items, unprocessedKeys, err := ddb.batchGetChunk(ctx, keys[start:end], options, stats)
+		if err != nil {
+			return nil, err
+		}
+		returnedList = append(returnedList, items...)
+		for _, key := range unprocessedKeys {
+			unprocessed = append(unprocessed, keyValueString(key[ddb.partitionKeyName]))
+		}
+	}
+
+	if len(unprocessed) > 0 {
+		return returnedList, &UnprocessedKeysError{PartitionKeyValues: unprocessed}
+	}
+
+	return returnedList, nil
+}
+
+func (ddb *DDBTable) batchWriteItems(ctx context.Context, items []map[string]interface{}, stats *BatchStats) error {
+	var chunk []types.WriteRequest
+	for _, item := range items {
+		dynamodbItem, err := ddb.marshalMap(item)
+		if err != nil {
+			return err
+		}
+		if err := ddb.prepareItem(dynamodbItem); err != nil {
+			return err
+		}
+
+		if len(chunk) == batchWriteMaxItems {
+			if err := ddb.batchWriteChunk(ctx, chunk, stats); err != nil {
+				return err
+			}
+			chunk = nil
+		}
+
+		chunk = append(chunk, types.WriteRequest{PutRequest: &types.PutRequest{Item: dynamodbItem}})
+	}
+
+	if len(chunk) > 0 {
+		return ddb.batchWriteChunk(ctx, chunk, stats)
+	}
+
+	return nil
+}
+
+func (ddb *DDBTable) batchGetChunk(ctx context.Context, keys []map[string]types.AttributeValue, options *batchGetOptions, stats *BatchStats) ([]map[string]interface{}, []map[string]types.AttributeValue, error) {
+	keysAndAttributes := types.KeysAndAttributes{
+		Keys:           keys,
+		ConsistentRead: ddb.consistentRead(options.consistentRead),
+	}
+	if len(options.projection) > 0 {
+		keysAndAttributes.ExpressionAttributeNames = make(map[string]string)
+		keysAndAttributes.ProjectionExpression = aws.String(projectionExpression(options.projection, keysAndAttributes.ExpressionAttributeNames))
+	}
+	requestItems := map[string]types.KeysAndAttributes{ddb.name: keysAndAttributes}
+
+	var returnedList []map[string]interface{}
+	backoff := batchInitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		input := &dynamodb.BatchGetItemInput{RequestItems: requestItems}
+		if stats != nil {
+			input.ReturnConsumedCapacity = types.ReturnConsumedCapacityTotal
+		}
+
+		result, err := ddb.batchGetItem(ctx, input)
+		if err != nil {
+			return nil, nil, err
+		}
+		stats.add(ddb.name, result.ConsumedCapacity)
+
+		for _, item := range result.Responses[ddb.name] {
+			returnedList = append(returnedList, ddb.convertItem(item))
+		}
+
+		if len(result.UnprocessedKeys) == 0 {
+			return returnedList, nil, nil
+		}
+		if attempt == batchMaxAttempts {
+			return returnedList, result.UnprocessedKeys[ddb.name].Keys, nil
+		}
+
+		requestItems = result.UnprocessedKeys
+		if err := sleepWithContext(ctx, backoff); err != nil {
+			return nil, nil, err
+		}
+		backoff *= 2
+	}
+}
+
+func (ddb *DDBTable) batchWriteChunk(ctx context.Context, requests []types.WriteRequest, stats *BatchStats) error {
+	requestItems := map[string][]types.WriteRequest{ddb.name: requests}
+	backoff := batchInitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		input := &dynamodb.BatchWriteItemInput{RequestItems: requestItems}
+		if stats != nil {
+			input.ReturnConsumedCapacity = types.ReturnConsumedCapacityTotal
+		}
+
+		result, err := ddb.batchWriteItem(ctx, input)
+		if err != nil {
+			return err
+		}
+		stats.add(ddb.name, result.ConsumedCapacity)
+
+		if len(result.UnprocessedItems) == 0 {
+			return nil
+		}
+		if attempt == batchMaxAttempts {
+			return fmt.Errorf("%d items still unprocessed after %d attempts", len(result.UnprocessedItems[ddb.name]), attempt)
+		}
+
+		requestItems = result.UnprocessedItems
+		if err := sleepWithContext(ctx, backoff); err != nil {
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+// add accounts for one request, a nil stats collects nothing.
+func (s *BatchStats) add(table string, consumed []types.ConsumedCapacity) {
+	if s == nil {
+		return
+	}
+
+	s.Requests++
+	for _, cc := range consumed {
+		if aws.ToString(cc.TableName) == table {
+			s.CapacityUnits += aws.ToFloat64(cc.CapacityUnits)
+		}
+	}
+}
+
+func (ddb *DDBTable) uniqueKeys(partitionKeyValues []string) ([]map[string]types.AttributeValue, error) {
+	seen := make(map[string]bool, len(partitionKeyValues))
+	keys := make([]map[string]types.AttributeValue, 0, len(partitionKeyValues))
+	for _, v := range partitionKeyValues {
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+
+		key, err := ddb.key(v)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,96 @@
+package go_dynamodb_wrapper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestBatchWriterRecoversFromFailedFlush(t *testing.T) {
+	ddb, fake := newFakeTable(t)
+	ctx := context.Background()
+	fake.fail("BatchWriteItem", 1)
+
+	w := NewBatchWriter(ddb)
+	for i := 0; i < 55; i++ {
+		err := w.Add(ctx, map[string]interface{}{"id": fmt.Sprint(i)})
+		if i == batchWriteMaxItems-1 {
+			if err == nil {
+				t.Fatalf("Add %d: the injected flush failure was not returned", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Add %d: %v", i, err)
+		}
+	}
+	if err := w.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// The failed request, 25 + 1 once the buffer overflowed, 25 and the rest.
+	if got := fake.calls("BatchWriteItem"); got != 5 {
+		t.Errorf("BatchWriteItem requests = %d, want 5", got)
+	}
+	if got := len(fake.items); got != 55 {
+		t.Errorf("stored %d items, want 55", got)
+	}
+	if len(w.pending) != 0 {
+		t.Errorf("%d items still buffered", len(w.pending))
+	}
+}
+
+func TestBatchWriteItemsChunking(t *testing.T) {
+	// The largest item prepareItem accepts, "id" and its value included.
+	largest := strings.Repeat("x", maxItemSize-len("id")-3-len("payload"))
+
+	tests := []struct {
+		name     string
+		count    int
+		payload  string
+		requests int
+	}{
+		{"one item", 1, "", 1},
+		{"a full batch", 25, "", 1},
+		{"one item over a batch", 26, "", 2},
+		{"several batches", 51, "", 3},
+		{"a full batch of the largest items", 25, largest, 1},
+		{"one largest item over a batch", 26, largest, 2},
+	}
+
+	for _, tt := range tests {
+		ddb, fake := newFakeTable(t)
+
+		items := make([]map[string]interface{}, tt.count)
+		for i := range items {
+			items[i] = map[string]interface{}{"id": fmt.Sprintf("%03d", i), "payload": tt.payload}
+		}
+		if err := ddb.BatchWriteItems(context.Background(), items); err != nil {
+			t.Fatalf("%s: BatchWriteItems: %v", tt.name, err)
+		}
+
+		if got := fake.calls("BatchWriteItem"); got != tt.requests {
+			t.Errorf("%s: BatchWriteItem requests = %d, want %d", tt.name, got, tt.requests)
+		}
+		if got := len(fake.items); got != tt.count {
+			t.Errorf("%s: stored %d items, want %d", tt.name, got, tt.count)
+		}
+	}
+}
+
+func TestBatchWriteItemsRejectsItemsOverTheLimit(t *testing.T) {
+	ddb, fake := newFakeTable(t)
+
+	items := []map[string]interface{}{
+		{"id": "000"},
+		{"id": "001", "payload": strings.Repeat("x", maxItemSize)},
+	}
+	if err := ddb.BatchWriteItems(context.Background(), items); !errors.Is(err, ErrItemTooLarge) {
+		t.Errorf("BatchWriteItems = %v, want ErrItemTooLarge", err)
+	}
+	if got := fake.calls("BatchWriteItem"); got != 0 {
+		t.Errorf("BatchWriteItem requests = %d, want 0", got)
+	}
+}
@@ -0,0 +1,98 @@
+package go_dynamodb_wrapper
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// CachedTable layers an in-process cache over ReadItem for tables whose data
+// hardly ever changes. Items are read with strong consistency and kept for the
+// configured duration. Every write made through the CachedTable, whatever the
+// method (batches, transactions and conditional writes included), evicts the
+// items it touched. Writes made through any other handle, including the table
+// passed to NewCachedTable, only show up once the entry expires. The returned
+// maps are shared with the cache and must not be modified.
+type CachedTable struct {
+	*DDBTable
+
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	// generation counts the evictions, a read only fills the cache when none
+	// happened while it was in flight.
+	generation uint64
+}
+
+type cacheEntry struct {
+	item    map[string]interface{}
+	expires time.Time
+}
+
+// NewCachedTable wraps a copy of table with a cache keeping items for ttl.
+func NewCachedTable(table *DDBTable, ttl time.Duration) *CachedTable {
+	cached := *table
+	c := &CachedTable{
+		DDBTable: &cached,
+		ttl:      ttl,
+		entries:  make(map[string]cacheEntry),
+	}
+	cached.written = c.evictItems
+
+	return c
+}
+
+// ReadItem returns the cached item when there is a fresh one and reads it
+// otherwise. Reads with WithProjection or WithoutCache always go to the table.
+func (c *CachedTable) ReadItem(partitionKeyValue string, opts ...ReadOption) (map[string]interface{}, error) {
+	options := newReadOptions(opts)
+	if options.bypassCache || len(options.projection) > 0 {
+		return c.DDBTable.ReadItem(partitionKeyValue, opts...)
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[partitionKeyValue]
+	generation := c.generation
+	c.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.item, nil
+	}
+
+	item, err := c.DDBTable.ReadItem(partitionKeyValue, append([]ReadOption{WithConsistentRead(true)}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if c.generation == generation {
+		c.entries[partitionKeyValue] = cacheEntry{item: item, expires: time.Now().Add(c.ttl)}
+	}
+	c.mu.Unlock()
+
+	return item, nil
+}
+
+// Evict drops the cached item with the given partition key, if any.
+func (c *CachedTable) Evict(partitionKeyValue string) {
+	c.mu.Lock()
+	delete(c.entries, partitionKeyValue)
+	c.generation++
+	c.mu.Unlock()
+}
+
+////////////////////////
+// Internal functions //
+////////////////////////
+
+// evictItems drops the cached items with the keys of the given items.
+func (c *CachedTable) evictItems(items ...map[string]types.AttributeValue) {
+	c.mu.Lock()
+	for _, item := range items {
+		if pk, ok := item[c.partitionKeyName]; ok {
+			delete(c.entries, keyValueString(pk))
+		}
+	}
+	c.generation++
+	c.mu.Unlock()
+}
@@ -0,0 +1,55 @@
+package go_dynamodb_wrapper
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCachedTableEvictsOnEveryWrite(t *testing.T) {
+	ddb, _ := newFakeTable(t)
+	c := NewCachedTable(ddb, time.Hour)
+	ctx := context.Background()
+
+	writes := map[string]func(value string) error{
+		"WriteItem": func(value string) error {
+			return c.WriteItem(map[string]interface{}{"id": "a", "v": value})
+		},
+		"WriteStruct": func(value string) error {
+			return c.WriteStruct(ctx, struct {
+				ID string `dynamodbav:"id"`
+				V  string `dynamodbav:"v"`
+			}{"a", value})
+		},
+		"BatchWriteItems": func(value string) error {
+			return c.BatchWriteItems(ctx, []map[string]interface{}{{"id": "a", "v": value}})
+		},
+	}
+
+	for name, write := range writes {
+		if err := write("old " + name); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if _, err := c.ReadItem("a"); err != nil {
+			t.Fatalf("ReadItem: %v", err)
+		}
+		if err := write("new " + name); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		item, err := c.ReadItem("a")
+		if err != nil {
+			t.Fatalf("ReadItem: %v", err)
+		}
+		if item["v"] != "new "+name {
+			t.Errorf("after %s, ReadItem returned v = %v from the cache", name, item["v"])
+		}
+	}
+
+	// Writes through the wrapped table bypass the cache.
+	if err := ddb.WriteItem(map[string]interface{}{"id": "a", "v": "elsewhere"}); err != nil {
+		t.Fatalf("WriteItem: %v", err)
+	}
+	if item, _ := c.ReadItem("a"); item["v"] == "elsewhere" {
+		t.Errorf("a write through the wrapped table evicted the cached item")
+	}
+}
@@ -0,0 +1,123 @@
+package go_dynamodb_wrapper
+
+import (
+	"context"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ConsumedCapacity returns the read and write capacity units consumed by all
+// operations of this table handle. It is only tracked when the table was created
+// with WithCapacityTracking, otherwise both values are 0.
+func (ddb *DDBTable) ConsumedCapacity() (read, write float64) {
+	if ddb.capacity == nil {
+		return 0, 0
+	}
+
+	return ddb.capacity.read.load(), ddb.capacity.write.load()
+}
+
+////////////////////////
+// Internal functions //
+////////////////////////
+
+type capacityCounter struct {
+	read  atomicFloat64
+	write atomicFloat64
+}
+
+type atomicFloat64 struct {
+	bits atomic.Uint64
+}
+
+func (f *atomicFloat64) add(delta float64) {
+	for {
+		old := f.bits.Load()
+		if f.bits.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+delta)) {
+			return
+		}
+	}
+}
+
+func (f *atomicFloat64) load() float64 {
+	return math.Float64frombits(f.bits.Load())
+}
+
+// returnConsumedCapacity keeps whatever the caller requested and otherwise asks
+// for the totals when tracking is enabled.
+func (ddb *DDBTable) returnConsumedCapacity(requested types.ReturnConsumedCapacity) types.ReturnConsumedCapacity {
+	if requested != "" || ddb.capacity == nil {
+		return requested
+	}
+
+	return types.ReturnConsumedCapacityTotal
+}
+
+func (ddb *DDBTable) recordReadCapacity(consumed ...*types.ConsumedCapacity) {
+	if ddb.capacity == nil {
+		return
+	}
+
+	for _, cc := range consumed {
+		if cc != nil {
+			ddb.capacity.read.add(aws.ToFloat64(cc.CapacityUnits))
+		}
+	}
+}
+
+func (ddb *DDBTable) recordWriteCapacity(consumed ...*types.ConsumedCapacity) {
+	if ddb.capacity == nil {
+		return
+	}
+
+	for _, cc := range consumed {
+		if cc != nil {
+			ddb.capacity.write.add(aws.ToFloat64(cc.CapacityUnits))
+		}
+	}
+}
+
+// capacityLimiter is a token bucket holding up to one second worth of capacity
+// units. Operations take their consumed capacity out of it and wait for the
+// bucket to refill whenever it runs into debt. A nil limiter never waits.
+type capacityLimiter struct {
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+func newCapacityLimiter(unitsPerSecond float64) *capacityLimiter {
+	if unitsPerSecond <= 0 {
+		return nil
+	}
+
+	return &capacityLimiter{rate: unitsPerSecond, tokens: unitsPerSecond, last: time.Now()}
+}
+
+func (l *capacityLimiter) wait(ctx context.Context, consumed *types.ConsumedCapacity) error {
+	if l == nil || consumed == nil {
+		return nil
+	}
+
+	now := time.Now()
+	l.tokens = math.Min(l.rate, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.tokens -= aws.ToFloat64(consumed.CapacityUnits)
+	l.last = now
+
+	if l.tokens >= 0 {
+		return nil
+	}
+
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	if err := sleepWithContext(ctx, delay); err != nil {
+		return err
+	}
+	l.tokens = 0
+	l.last = time.Now()
+
+	return nil
+}
@@ -5,60 +5,122 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
-	"reflect"
+	"math/big"
+	"regexp"
+	"strconv"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
 type DDBTable struct {
-	region           string
-	name             string
-	partitionKeyName string
-	client           *dynamodb.Client
+	region              string
+	name                string
+	partitionKeyName    string
+	partitionKeyType    types.ScalarAttributeType
+	sortKeyName         string
+	versionAttribute    string
+	autoCreateTable     bool
+	defaultTTLAttribute string
+	defaultTTL          time.Duration
+	converter           converter
+	consistentReads     bool
+	writeShards         int
+	valueCodecs         map[string]ValueCodec
+	createdAttribute    string
+	updatedAttribute    string
+	storedNames         map[string]string
+	redactedAttributes  map[string]bool
+	endpoint            string
+	configOptions       []func(*config.LoadOptions) error
+	capacity            *capacityCounter
+	encoderOptions      []func(*attributevalue.EncoderOptions)
+	decoderOptions      []func(*attributevalue.DecoderOptions)
+	awsConfig           aws.Config
+	client              *dynamodb.Client
+	fallbackRegion      string
+	fallbackClient      *dynamodb.Client
+	// written, when set, is called with the key or the whole item of every
+	// item a write request touched, once the request returned.
+	written func(items ...map[string]types.AttributeValue)
 }
 
-func NewTable(region, name, partitionKeyName string) (*DDBTable, error) {
-	if region == "" || name == "" || partitionKeyName == "" {
-		return nil, errors.New("you must specify all values: region, name & partition_key name")
+func NewTable(region, name, partitionKeyName string, opts ...Option) (*DDBTable, error) {
+	if name == "" || partitionKeyName == "" {
+		return nil, errors.New("you must specify all values: name & partition_key name")
 	}
 
-	// Create DynamoDB client
-	cfg, err := config.LoadDefaultConfig(context.Background(), config.WithRegion(region))
+	ddb := &DDBTable{
+		region:           region,
+		name:             name,
+		partitionKeyName: partitionKeyName,
+		partitionKeyType: types.ScalarAttributeTypeS,
+		versionAttribute: DefaultVersionAttribute,
+	}
+	for _, opt := range opts {
+		opt(ddb)
+	}
+
+	cached, err := ddb.newClient(region)
 	if err != nil {
-		return nil, fmt.Errorf("unable to load AWS SDK config: %v", err)
+		return nil, err
 	}
+	ddb.awsConfig = cached.cfg
+	ddb.client = cached.client
+	ddb.region = cached.cfg.Region
 
-	client := dynamodb.NewFromConfig(cfg)
+	if ddb.fallbackRegion != "" {
+		fallback, err := ddb.newClient(ddb.fallbackRegion)
+		if err != nil {
+			return nil, err
+		}
+		ddb.fallbackClient = fallback.client
+	}
 
-	return &DDBTable{
-		region:           region,
-		name:             name,
-		partitionKeyName: partitionKeyName,
-		client:           client,
-	}, nil
+	return ddb, nil
 }
 
-func (ddb *DDBTable) ReadPartitionKeysList() ([]string, error) {
+// ReadPartitionKeysList returns the partition key of every item, or of the
+// first ones with WithScanLimit, paced by WithScanRateLimit. With WithScanIndex
+// it scans the given index instead of the table, which costs less when the
+// index is small and projects the partition key. Indexes are sparse though: an
+// item lacking the index key attributes isn't in the index, so the list is only
+// complete when every item carries them. WithScanResumeTokens is rejected, it
+// only applies to ScanTableParallel.
+func (ddb *DDBTable) ReadPartitionKeysList(opts ...ScanOption) ([]string, error) {
+	return ddb.ReadPartitionKeysListWithContext(context.Background(), opts...)
+}
+
+// ReadPartitionKeysListWithContext works like ReadPartitionKeysList but stops
+// paginating as soon as ctx is done.
+func (ddb *DDBTable) ReadPartitionKeysListWithContext(ctx context.Context, opts ...ScanOption) ([]string, error) {
 	var partitionKeys []string
-	var lastEvaluatedKey map[string]types.AttributeValue
 
-	for {
-		input := &dynamodb.ScanInput{
-			TableName:            aws.String(ddb.name),
-			ProjectionExpression: aws.String(ddb.partitionKeyName),
-			ExclusiveStartKey:    lastEvaluatedKey,
+	options := newScanOptions(opts)
+	if options.resumeTokens != nil {
+		return []string{}, errors.New("WithScanResumeTokens only applies to ScanTableParallel")
+	}
+
+	input := ddb.scanInput(options)
+	input.ProjectionExpression = aws.String("#pk")
+	input.ExpressionAttributeNames = mergeMaps(input.ExpressionAttributeNames, map[string]string{"#pk": ddb.partitionKeyName})
+
+	pages := NewPaginator(ddb.pacedScanPages(input, options.readRate), rawPageItem)
+	for pages.HasMore() {
+		if options.limit > 0 {
+			input.Limit = aws.Int32(int32(options.limit - len(partitionKeys)))
 		}
 
-		result, err := ddb.client.Scan(context.Background(), input)
+		items, err := pages.NextPage(ctx)
 		if err != nil {
 			return []string{}, err
 		}
 
-		for _, item := range result.Items {
+		for _, item := range items {
 			if pk, ok := item[ddb.partitionKeyName]; ok {
 				if s, ok := pk.(*types.AttributeValueMemberS); ok {
 					partitionKeys = append(partitionKeys, s.Value)
@@ -66,63 +128,268 @@ func (ddb *DDBTable) ReadPartitionKeysList() ([]string, error) {
 			}
 		}
 
-		if result.LastEvaluatedKey == nil {
+		if options.limit > 0 && len(partitionKeys) >= options.limit {
+			partitionKeys = partitionKeys[:options.limit]
 			break
 		}
-		lastEvaluatedKey = result.LastEvaluatedKey
 	}
 
 	return partitionKeys, nil
 }
 
-func (ddb *DDBTable) ScanTable() ([]map[string]interface{}, error) {
+// PartitionKeyCounts scans the whole table and returns how many items each
+// partition key value holds. On tables with a sort key this shows how evenly the
+// data is spread, making hot partitions easy to spot before they get throttled.
+func (ddb *DDBTable) PartitionKeyCounts(ctx context.Context) (map[string]int, error) {
 	input := &dynamodb.ScanInput{
-		TableName: aws.String(ddb.name),
+		TableName:                aws.String(ddb.name),
+		ConsistentRead:           ddb.consistentRead(nil),
+		ProjectionExpression:     aws.String("#pk"),
+		ExpressionAttributeNames: map[string]string{"#pk": ddb.partitionKeyName},
+	}
+
+	counts := make(map[string]int)
+	pages := NewPaginator(ddb.scanPages(input), rawPageItem)
+	for pages.HasMore() {
+		items, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			if pk, ok := item[ddb.partitionKeyName]; ok {
+				counts[keyValueString(pk)]++
+			}
+		}
 	}
 
-	result, err := ddb.client.Scan(context.Background(), input)
+	return counts, nil
+}
+
+func (ddb *DDBTable) ScanTable(opts ...ScanOption) ([]map[string]interface{}, error) {
+	return ddb.ScanTableWithContext(context.Background(), opts...)
+}
+
+// ScanTableWithContext works like ScanTable but stops paginating as soon as ctx
+// is done.
+func (ddb *DDBTable) ScanTableWithContext(ctx context.Context, opts ...ScanOption) ([]map[string]interface{}, error) {
+	returnedList, _, err := ddb.scanAll(ctx, newScanOptions(opts))
 	if err != nil {
 		return make([]map[string]interface{}, 0), err
 	}
 
-	var returnedList []map[string]interface{}
+	return returnedList, nil
+}
+
+// ScanTableWithStats works like ScanTable but also returns how many items were
+// scanned, a count far above the number of returned items shows an inefficient filter.
+func (ddb *DDBTable) ScanTableWithStats(opts ...ScanOption) ([]map[string]interface{}, int64, error) {
+	return ddb.scanAll(context.Background(), newScanOptions(opts))
+}
 
-	for _, item := range result.Items {
-		returnedList = append(returnedList, convertDynamoDBJSONToMap(item))
+// ScanRaw runs a scan built by the caller, for the features ScanOption doesn't
+// cover. The table name is filled in and all pages are read, everything else is
+// sent as is; input itself is left unmodified.
+func (ddb *DDBTable) ScanRaw(ctx context.Context, input *dynamodb.ScanInput) ([]map[string]interface{}, error) {
+	in := *input
+	in.TableName = aws.String(ddb.name)
+
+	var returnedList []map[string]interface{}
+	pages := NewPaginator(ddb.scanPages(&in), ddb.convertPageItem)
+	pages.startKey = input.ExclusiveStartKey
+	for pages.HasMore() {
+		items, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		returnedList = append(returnedList, items...)
 	}
 
 	return returnedList, nil
 }
 
-func (ddb *DDBTable) ReadItem(partitionKeyValue string) (map[string]interface{}, error) {
+// ReadItem reads an item converted into a plain map. The other read methods
+// follow the same naming: ReadItemRaw returns the SDK's attribute values as is,
+// ReadItemTyped wraps them in an Item with typed getters and ReadStruct
+// unmarshals into a struct.
+func (ddb *DDBTable) ReadItem(partitionKeyValue string, opts ...ReadOption) (map[string]interface{}, error) {
+	item, err := ddb.ReadItemRaw(context.Background(), partitionKeyValue, opts...)
+	if errors.Is(err, ErrItemNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get item: %w", err)
+	}
+
+	return ddb.convertItem(item), nil
+}
+
+// ReadItemRaw reads an item without any conversion.
+func (ddb *DDBTable) ReadItemRaw(ctx context.Context, partitionKeyValue string, opts ...ReadOption) (map[string]types.AttributeValue, error) {
+	input, err := ddb.getItemInput(partitionKeyValue, newReadOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := ddb.getItem(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Item) == 0 {
+		return nil, ErrItemNotFound
+	}
+
+	return result.Item, nil
+}
+
+// ReadItemInto reads an item into dst, which is cleared first, so that hot loops
+// can reuse a single map instead of allocating one per read.
+func (ddb *DDBTable) ReadItemInto(ctx context.Context, partitionKeyValue string, dst map[string]interface{}) error {
+	item, err := ddb.ReadItemRaw(ctx, partitionKeyValue)
+	if err != nil {
+		return err
+	}
+
+	clear(dst)
+	for k, v := range item {
+		dst[ddb.converter.name(k)] = ddb.converter.attribute(k, v)
+	}
+
+	return nil
+}
+
+// ReadItemWithFallback first tries a strongly consistent read limited to
+// strongTimeout and, should it time out, falls back to an eventually consistent
+// read. The returned bool tells whether the item came from the consistent read.
+func (ddb *DDBTable) ReadItemWithFallback(ctx context.Context, partitionKeyValue string, strongTimeout time.Duration) (map[string]interface{}, bool, error) {
+	strongCtx, cancel := context.WithTimeout(ctx, strongTimeout)
+	defer cancel()
+
+	item, err := ddb.ReadItemRaw(strongCtx, partitionKeyValue, WithConsistentRead(true))
+	if err == nil {
+		return ddb.convertItem(item), true, nil
+	}
+	if strongCtx.Err() != context.DeadlineExceeded || ctx.Err() != nil {
+		return nil, false, err
+	}
+
+	item, err = ddb.ReadItemRaw(ctx, partitionKeyValue, WithConsistentRead(false))
+	if err != nil {
+		return nil, false, err
+	}
+
+	return ddb.convertItem(item), false, nil
+}
+
+// ReadItemKey reads an item by a partition key of any type, bypassing the
+// configured partition key type.
+func (ddb *DDBTable) ReadItemKey(ctx context.Context, partitionKey types.AttributeValue) (map[string]interface{}, error) {
 	input := &dynamodb.GetItemInput{
-		TableName: aws.String(ddb.name),
-		Key: map[string]types.AttributeValue{
-			ddb.partitionKeyName: &types.AttributeValueMemberS{
-				Value: partitionKeyValue,
-			},
-		},
+		TableName:      aws.String(ddb.name),
+		Key:            map[string]types.AttributeValue{ddb.partitionKeyName: partitionKey},
+		ConsistentRead: ddb.consistentRead(nil),
+	}
+
+	result, err := ddb.getItem(ctx, input)
+	if err != nil {
+		return nil, err
 	}
+	if len(result.Item) == 0 {
+		return nil, ErrItemNotFound
+	}
+
+	return ddb.convertItem(result.Item), nil
+}
 
-	result, err := ddb.client.GetItem(context.Background(), input)
+// ReadAttribute reads a single attribute of an item, projecting away everything else.
+func (ddb *DDBTable) ReadAttribute(ctx context.Context, partitionKeyValue, attributeName string) (interface{}, error) {
+	av, err := ddb.readAttribute(ctx, partitionKeyValue, attributeName)
 	if err != nil {
-		return nil, errors.New("failed to get item")
+		return nil, err
 	}
-	if result.Item == nil {
-		return nil, errors.New("item not found")
+
+	return ddb.converter.attribute(attributeName, av), nil
+}
+
+// ReadInt reads a number attribute that holds an integer. Unlike ReadAttribute,
+// which returns numbers as strings by default, it fails when the number has a
+// fractional part or doesn't fit an int64.
+func (ddb *DDBTable) ReadInt(ctx context.Context, partitionKeyValue, attributeName string) (int64, error) {
+	av, err := ddb.readAttribute(ctx, partitionKeyValue, attributeName)
+	if err != nil {
+		return 0, err
+	}
+
+	n, ok := av.(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, fmt.Errorf("attribute %q is not a number", attributeName)
+	}
+	i, err := strconv.ParseInt(n.Value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("attribute %q is not an int64: %w", attributeName, err)
+	}
+
+	return i, nil
+}
+
+// ReadJSONAttribute decodes a JSON document stored in a string attribute into out.
+func (ddb *DDBTable) ReadJSONAttribute(ctx context.Context, partitionKeyValue, attributeName string, out interface{}) error {
+	value, err := ddb.ReadAttribute(ctx, partitionKeyValue, attributeName)
+	if err != nil {
+		return err
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("attribute %q is not a string", attributeName)
+	}
+
+	if err := json.Unmarshal([]byte(s), out); err != nil {
+		return fmt.Errorf("attribute %q does not hold valid JSON: %w", attributeName, err)
+	}
+
+	return nil
+}
+
+// ItemExists reports whether an item with the given partition key exists, only
+// fetching the key attribute.
+func (ddb *DDBTable) ItemExists(ctx context.Context, partitionKeyValue string) (bool, error) {
+	key, err := ddb.key(partitionKeyValue)
+	if err != nil {
+		return false, err
+	}
+
+	input := &dynamodb.GetItemInput{
+		TableName:                aws.String(ddb.name),
+		Key:                      key,
+		ConsistentRead:           ddb.consistentRead(nil),
+		ProjectionExpression:     aws.String("#pk"),
+		ExpressionAttributeNames: map[string]string{"#pk": ddb.partitionKeyName},
+	}
+
+	result, err := ddb.getItem(ctx, input)
+	if err != nil {
+		return false, err
 	}
 
-	return convertDynamoDBJSONToMap(result.Item), nil
+	return len(result.Item) > 0, nil
 }
 
 func (ddb *DDBTable) WriteItem(item map[string]interface{}) error {
-	dynamodbItem := convertToDynamoDBJSON(item)
+	dynamodbItem, err := ddb.marshalMap(item)
+	if err != nil {
+		return err
+	}
+	if err := ddb.prepareItem(dynamodbItem); err != nil {
+		return err
+	}
+
 	input := &dynamodb.PutItemInput{
 		TableName: aws.String(ddb.name),
 		Item:      dynamodbItem,
 	}
 
-	_, err := ddb.client.PutItem(context.Background(), input)
+	_, err = ddb.putItem(context.Background(), input)
 	if err != nil {
 		return err
 	}
@@ -131,29 +398,22 @@ func (ddb *DDBTable) WriteItem(item map[string]interface{}) error {
 }
 
 func (ddb *DDBTable) UpdateItem(partitionKeyValue string, updatedValue map[string]interface{}) error {
-	dynamoDBUpdateValues := convertToDynamoDBJSON(updatedValue)
-	updateExpression := "SET "
-	expressionAttributeValues := make(map[string]types.AttributeValue)
-	expressionAttributeNames := make(map[string]string)
-	i := 1
-	for k := range dynamoDBUpdateValues {
-		updateExpression += fmt.Sprintf("#k%d = :v%d, ", i, i)
-		expressionAttributeValues[fmt.Sprintf(":v%d", i)] = dynamoDBUpdateValues[k]
-		expressionAttributeNames[fmt.Sprintf("#k%d", i)] = k
-		i++
+	key, err := ddb.key(partitionKeyValue)
+	if err != nil {
+		return err
 	}
 
-	updateExpression = updateExpression[:len(updateExpression)-2]
-
-	input := &dynamodb.UpdateItemInput{
-		TableName:                 aws.String(ddb.name),
-		Key:                       map[string]types.AttributeValue{ddb.partitionKeyName: &types.AttributeValueMemberS{Value: partitionKeyValue}},
-		UpdateExpression:          aws.String(updateExpression),
-		ExpressionAttributeValues: expressionAttributeValues,
-		ExpressionAttributeNames:  expressionAttributeNames,
+	values, err := ddb.marshalMap(updatedValue)
+	if err != nil {
+		return err
+	}
+	if err := ddb.prepareUpdate(values); err != nil {
+		return err
 	}
 
-	_, err := ddb.client.UpdateItem(context.Background(), input)
+	input := ddb.setUpdateInput(key, values)
+
+	_, err = ddb.updateItem(context.Background(), input)
 	if err != nil {
 		return err
 	}
@@ -161,17 +421,50 @@ func (ddb *DDBTable) UpdateItem(partitionKeyValue string, updatedValue map[strin
 	return nil
 }
 
+// UpdateItemExpr updates an item with a caller supplied UpdateExpression, which
+// may use any of SET, REMOVE, ADD and DELETE. names and values fill the
+// expression's #name and :value placeholders and may be nil. With WithTimestamps
+// the update also sets the updated attribute, using the #updatedAt and
+// :updatedAt placeholders.
+func (ddb *DDBTable) UpdateItemExpr(ctx context.Context, partitionKeyValue, updateExpression string, names map[string]string, values map[string]interface{}) error {
+	key, err := ddb.key(partitionKeyValue)
+	if err != nil {
+		return err
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName:        aws.String(ddb.name),
+		Key:              key,
+		UpdateExpression: aws.String(updateExpression),
+	}
+	if len(names) > 0 {
+		input.ExpressionAttributeNames = names
+	}
+	if len(values) > 0 {
+		expressionAttributeValues, err := ddb.marshalMap(values)
+		if err != nil {
+			return err
+		}
+		input.ExpressionAttributeValues = expressionAttributeValues
+	}
+	input.UpdateExpression, input.ExpressionAttributeNames, input.ExpressionAttributeValues = ddb.stampUpdateExpression(updateExpression, input.ExpressionAttributeNames, input.ExpressionAttributeValues)
+
+	_, err = ddb.updateItem(ctx, input)
+	return err
+}
+
 func (ddb *DDBTable) DeleteItem(partitionKeyValue string) error {
+	key, err := ddb.key(partitionKeyValue)
+	if err != nil {
+		return err
+	}
+
 	input := &dynamodb.DeleteItemInput{
 		TableName: aws.String(ddb.name),
-		Key: map[string]types.AttributeValue{
-			ddb.partitionKeyName: &types.AttributeValueMemberS{
-				Value: partitionKeyValue,
-			},
-		},
+		Key:       key,
 	}
 
-	_, err := ddb.client.DeleteItem(context.Background(), input)
+	_, err = ddb.deleteItem(context.Background(), input)
 	if err != nil {
 		return err
 	}
@@ -208,76 +501,361 @@ func DDBTablesList(awsRegion string) ([]string, error) {
 // Internal functions //
 ////////////////////////
 
-func convertToDynamoDBJSON(regularJSON map[string]interface{}) map[string]types.AttributeValue {
-	dynamodbJSON := make(map[string]types.AttributeValue)
-	for k, v := range regularJSON {
-		dynamodbJSON[k] = convertValue(v)
+func (ddb *DDBTable) scanInput(options *scanOptions) *dynamodb.ScanInput {
+	input := &dynamodb.ScanInput{
+		TableName:      aws.String(ddb.name),
+		ConsistentRead: ddb.consistentRead(options.consistentRead),
+	}
+	if options.indexName != "" {
+		// The table default doesn't apply, global indexes would reject it.
+		input.IndexName = aws.String(options.indexName)
+		input.ConsistentRead = options.consistentRead
+	}
+	if options.filterExpression != "" {
+		input.FilterExpression = aws.String(options.filterExpression)
+		input.ExpressionAttributeNames = options.expressionNames
+		input.ExpressionAttributeValues = options.expressionValues
+	}
+	if options.readRate > 0 {
+		input.ReturnConsumedCapacity = types.ReturnConsumedCapacityTotal
+	}
+
+	return input
+}
+
+func (ddb *DDBTable) scanAll(ctx context.Context, options *scanOptions) ([]map[string]interface{}, int64, error) {
+	input := ddb.scanInput(options)
+
+	var returnedList []map[string]interface{}
+	var scannedCount int64
+	limiter := newCapacityLimiter(options.readRate)
+
+	pages := NewPaginator(ddb.scanPagesWith(input, func(result *dynamodb.ScanOutput) error {
+		scannedCount += int64(result.ScannedCount)
+		return limiter.wait(ctx, result.ConsumedCapacity)
+	}), ddb.convertPageItem)
+
+	for pages.HasMore() {
+		if options.limit > 0 {
+			input.Limit = aws.Int32(int32(options.limit - len(returnedList)))
+		}
+
+		items, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+		returnedList = append(returnedList, items...)
+
+		if options.limit > 0 && len(returnedList) >= options.limit {
+			returnedList = returnedList[:options.limit]
+			break
+		}
+	}
+
+	return returnedList, scannedCount, nil
+}
+
+func (ddb *DDBTable) readAttribute(ctx context.Context, partitionKeyValue, attributeName string) (types.AttributeValue, error) {
+	key, err := ddb.key(partitionKeyValue)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &dynamodb.GetItemInput{
+		TableName:            aws.String(ddb.name),
+		Key:                  key,
+		ConsistentRead:       ddb.consistentRead(nil),
+		ProjectionExpression: aws.String("#pk, #attr"),
+		ExpressionAttributeNames: map[string]string{
+			"#pk":   ddb.partitionKeyName,
+			"#attr": attributeName,
+		},
+	}
+
+	result, err := ddb.getItem(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Item) == 0 {
+		return nil, ErrItemNotFound
+	}
+
+	av, ok := result.Item[attributeName]
+	if !ok {
+		return nil, ErrAttributeNotFound
 	}
 
-	return dynamodbJSON
+	return av, nil
+}
+
+func (ddb *DDBTable) getItemInput(partitionKeyValue string, options *readOptions) (*dynamodb.GetItemInput, error) {
+	key, err := ddb.key(partitionKeyValue)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &dynamodb.GetItemInput{
+		TableName:      aws.String(ddb.name),
+		Key:            key,
+		ConsistentRead: ddb.consistentRead(options.consistentRead),
+	}
+	if len(options.projection) > 0 {
+		input.ExpressionAttributeNames = make(map[string]string)
+		input.ProjectionExpression = aws.String(projectionExpression(options.projection, input.ExpressionAttributeNames))
+	}
+
+	return input, nil
+}
+
+// consistentRead resolves the ConsistentRead flag of a read, a per-call choice
+// takes precedence over the table default.
+func (ddb *DDBTable) consistentRead(override *bool) *bool {
+	if override != nil {
+		return override
+	}
+	if ddb.consistentReads {
+		return aws.Bool(true)
+	}
+	return nil
+}
+
+// prepareItem validates an item about to be written and adds the attributes the
+// table is configured to maintain.
+func (ddb *DDBTable) prepareItem(item map[string]types.AttributeValue) error {
+	ddb.storeNames(item)
+	if err := ddb.checkItemKey(item); err != nil {
+		return err
+	}
+	if err := ddb.shardItem(item); err != nil {
+		return err
+	}
+	if err := ddb.encodeAttributes(item); err != nil {
+		return err
+	}
+
+	if ddb.defaultTTLAttribute != "" {
+		if _, ok := item[ddb.defaultTTLAttribute]; !ok {
+			expiresAt := time.Now().Add(ddb.defaultTTL).Unix()
+			item[ddb.defaultTTLAttribute] = &types.AttributeValueMemberN{Value: strconv.FormatInt(expiresAt, 10)}
+		}
+	}
+
+	if ddb.updatedAttribute != "" {
+		now := epochNow()
+		if _, ok := item[ddb.createdAttribute]; !ok && ddb.createdAttribute != "" {
+			item[ddb.createdAttribute] = now
+		}
+		item[ddb.updatedAttribute] = now
+	}
+
+	if size := itemSize(item); size > maxItemSize {
+		return fmt.Errorf("%w: %d bytes, at most %d allowed", ErrItemTooLarge, size, maxItemSize)
+	}
+
+	return nil
+}
+
+// prepareUpdate is the counterpart of prepareItem for the attributes of an
+// update.
+func (ddb *DDBTable) prepareUpdate(values map[string]types.AttributeValue) error {
+	ddb.storeNames(values)
+	if err := ddb.encodeAttributes(values); err != nil {
+		return err
+	}
+	if ddb.updatedAttribute != "" {
+		values[ddb.updatedAttribute] = epochNow()
+	}
+
+	return nil
+}
+
+// storeNames renames the attributes of an item about to be written from the
+// names the caller uses to the stored ones, undoing WithAttributeNames.
+func (ddb *DDBTable) storeNames(item map[string]types.AttributeValue) {
+	if len(ddb.storedNames) == 0 {
+		return
+	}
+
+	renamed := make(map[string]types.AttributeValue)
+	for name, av := range item {
+		if storedName, ok := ddb.storedNames[name]; ok {
+			renamed[storedName] = av
+			delete(item, name)
+		}
+	}
+	for name, av := range renamed {
+		item[name] = av
+	}
+}
+
+// epochNow is the current time as stored in timestamp attributes.
+func epochNow() types.AttributeValue {
+	return &types.AttributeValueMemberN{Value: strconv.FormatInt(time.Now().Unix(), 10)}
+}
+
+// setClause finds the SET keyword of an update expression. SET is a reserved
+// word, so apart from the keyword it can only show up inside a #name or :value
+// placeholder, which is why a preceding # or : (or any word character) is
+// excluded.
+var setClause = regexp.MustCompile(`(?i)(?:^|[^#:\w])SET\s+`)
+
+// stampUpdateExpression adds the updated timestamp to an update expression and
+// its placeholders, inside its SET clause when there is one since a clause may
+// only appear once. names and values are not modified.
+func (ddb *DDBTable) stampUpdateExpression(expression string, names map[string]string, values map[string]types.AttributeValue) (*string, map[string]string, map[string]types.AttributeValue) {
+	if ddb.updatedAttribute == "" {
+		return aws.String(expression), names, values
+	}
+
+	names = mergeMaps(names, map[string]string{"#updatedAt": ddb.updatedAttribute})
+	values = mergeMaps(values, map[string]types.AttributeValue{":updatedAt": epochNow()})
+
+	if loc := setClause.FindStringIndex(expression); loc != nil {
+		expression = expression[:loc[1]] + "#updatedAt = :updatedAt, " + expression[loc[1]:]
+	} else {
+		expression = "SET #updatedAt = :updatedAt " + expression
+	}
+	return aws.String(expression), names, values
+}
+
+// setUpdateInput builds an UpdateItem request that SETs every given attribute.
+func (ddb *DDBTable) setUpdateInput(key, values map[string]types.AttributeValue) *dynamodb.UpdateItemInput {
+	updateExpression := "SET "
+	expressionAttributeValues := make(map[string]types.AttributeValue)
+	expressionAttributeNames := make(map[string]string)
+	i := 1
+	for k := range values {
+		updateExpression += fmt.Sprintf("#k%d = :v%d, ", i, i)
+		expressionAttributeValues[fmt.Sprintf(":v%d", i)] = values[k]
+		expressionAttributeNames[fmt.Sprintf("#k%d", i)] = k
+		i++
+	}
+
+	updateExpression = updateExpression[:len(updateExpression)-2]
+
+	return &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(ddb.name),
+		Key:                       key,
+		UpdateExpression:          aws.String(updateExpression),
+		ExpressionAttributeValues: expressionAttributeValues,
+		ExpressionAttributeNames:  expressionAttributeNames,
+	}
 }
 
 func convertDynamoDBJSONToMap(attributes map[string]types.AttributeValue) map[string]interface{} {
+	return converter{}.toMap(attributes)
+}
+
+// converter turns attribute values into the plain Go values returned by the map
+// based methods, according to the table's conversion options.
+type converter struct {
+	parseNumbers bool
+	codecs       map[string]AttributeCodec
+	names        map[string]string
+}
+
+func (ddb *DDBTable) convertItem(attributes map[string]types.AttributeValue) map[string]interface{} {
+	return ddb.converter.item(attributes)
+}
+
+// item converts a whole item, decoding the top level attributes that have a
+// codec. Values that fail to decode are returned as stored.
+func (c converter) item(attributes map[string]types.AttributeValue) map[string]interface{} {
 	result := make(map[string]interface{})
 	for k, v := range attributes {
-		switch val := v.(type) {
-		case *types.AttributeValueMemberS:
-			result[k] = val.Value
-		case *types.AttributeValueMemberN:
-			result[k] = val.Value
-		case *types.AttributeValueMemberBOOL:
-			result[k] = fmt.Sprintf("%v", val.Value)
-		case *types.AttributeValueMemberM:
-			jsonStr, _ := json.Marshal(convertDynamoDBJSONToMap(val.Value))
-			result[k] = string(jsonStr)
-		case *types.AttributeValueMemberL:
-			result[k] = convertDynamoDBListToSlice(val.Value)
-		default:
-			result[k] = fmt.Sprintf("%v", v)
+		result[c.name(k)] = c.attribute(k, v)
+	}
+	return result
+}
+
+// name returns the name a stored top level attribute is returned under.
+func (c converter) name(storedName string) string {
+	if name, ok := c.names[storedName]; ok {
+		return name
+	}
+	return storedName
+}
+
+func (c converter) attribute(name string, av types.AttributeValue) interface{} {
+	if codec, ok := c.codecs[name]; ok {
+		if b, ok := av.(*types.AttributeValueMemberB); ok {
+			if decoded, err := codec.Decode(b.Value); err == nil {
+				return decoded
+			}
 		}
 	}
+	return c.value(av)
+}
+
+func (c converter) toMap(attributes map[string]types.AttributeValue) map[string]interface{} {
+	result := make(map[string]interface{})
+	for k, v := range attributes {
+		result[k] = c.value(v)
+	}
 	return result
 }
 
-func convertDynamoDBListToSlice(list []types.AttributeValue) []interface{} {
-	var result []interface{}
+func (c converter) toSlice(list []types.AttributeValue) []interface{} {
+	result := make([]interface{}, 0, len(list))
 	for _, item := range list {
-		switch val := item.(type) {
-		case *types.AttributeValueMemberS:
-			result = append(result, val.Value)
-		case *types.AttributeValueMemberN:
-			result = append(result, val.Value)
-		case *types.AttributeValueMemberBOOL:
-			result = append(result, fmt.Sprintf("%v", val.Value))
-		case *types.AttributeValueMemberM:
-			result = append(result, convertDynamoDBJSONToMap(val.Value))
-		case *types.AttributeValueMemberL:
-			result = append(result, convertDynamoDBListToSlice(val.Value))
-		default:
-			result = append(result, fmt.Sprintf("%v", item))
-		}
+		result = append(result, c.value(item))
 	}
 	return result
 }
 
-func convertValue(value interface{}) types.AttributeValue {
-	switch v := value.(type) {
-	case string:
-		return &types.AttributeValueMemberS{Value: v}
-	case bool:
-		return &types.AttributeValueMemberBOOL{Value: v}
-	case int, int8, int16, int32, int64, float32, float64:
-		return &types.AttributeValueMemberN{Value: fmt.Sprintf("%v", v)}
-	case map[string]interface{}:
-		return &types.AttributeValueMemberM{Value: convertToDynamoDBJSON(v)}
-	case []interface{}:
-		var listValues []types.AttributeValue
-		for _, item := range v {
-			listValues = append(listValues, convertValue(item))
+// value converts a single value the same way at any nesting level, so that
+// lists of maps of lists etc. keep their structure.
+func (c converter) value(av types.AttributeValue) interface{} {
+	switch val := av.(type) {
+	case *types.AttributeValueMemberS:
+		return val.Value
+	case *types.AttributeValueMemberN:
+		if c.parseNumbers {
+			return parseNumber(val.Value)
 		}
-		return &types.AttributeValueMemberL{Value: listValues}
-	default:
-		log.Fatalf("Unsupported type: %v", reflect.TypeOf(v))
+		return val.Value
+	case *types.AttributeValueMemberBOOL:
+		return fmt.Sprintf("%v", val.Value)
+	case *types.AttributeValueMemberNULL:
 		return nil
+	case *types.AttributeValueMemberB:
+		return val.Value
+	case *types.AttributeValueMemberM:
+		return c.toMap(val.Value)
+	case *types.AttributeValueMemberL:
+		return c.toSlice(val.Value)
+	case *types.AttributeValueMemberSS:
+		return StringSet(val.Value)
+	case *types.AttributeValueMemberNS:
+		return NumberSet(val.Value)
+	case *types.AttributeValueMemberBS:
+		return BinarySet(val.Value)
+	default:
+		return fmt.Sprintf("%v", av)
+	}
+}
+
+// parseNumber returns a number as int64 or float64 when that type holds it
+// exactly, and as its original string otherwise (integers beyond int64, more
+// digits than a float64 carries, ...), so no precision is ever silently lost.
+func parseNumber(s string) interface{} {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return s
 	}
+
+	original, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return s
+	}
+	parsed, ok := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	if !ok || original.Cmp(parsed) != 0 {
+		return s
+	}
+
+	return f
 }
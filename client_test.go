@@ -0,0 +1,64 @@
+package go_dynamodb_wrapper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWriteItemScanTableStringSetRoundTrip(t *testing.T) {
+	ddb, _ := newFakeTable(t)
+
+	if err := ddb.WriteItem(map[string]interface{}{"id": "a", "tags": []string{"red", "blue"}}); err != nil {
+		t.Fatalf("WriteItem: %v", err)
+	}
+
+	items, err := ddb.ScanTable()
+	if err != nil {
+		t.Fatalf("ScanTable: %v", err)
+	}
+	want := []map[string]interface{}{{"id": "a", "tags": StringSet{"red", "blue"}}}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("ScanTable = %#v, want %#v", items, want)
+	}
+}
+
+func TestWriteItemRejectsEmptyStringSet(t *testing.T) {
+	ddb, _ := newFakeTable(t)
+
+	for _, tags := range []interface{}{[]string{}, StringSet{}} {
+		if err := ddb.WriteItem(map[string]interface{}{"id": "a", "tags": tags}); err == nil {
+			t.Errorf("WriteItem with empty %T succeeded", tags)
+		}
+	}
+}
+
+func TestStampUpdateExpression(t *testing.T) {
+	ddb := &DDBTable{updatedAttribute: "updated"}
+
+	tests := []struct {
+		in, want string
+	}{
+		{"SET #a = :a", "SET #updatedAt = :updatedAt, #a = :a"},
+		{"set #a = :a REMOVE #b", "set #updatedAt = :updatedAt, #a = :a REMOVE #b"},
+		{"REMOVE #b SET #a = :a", "REMOVE #b SET #updatedAt = :updatedAt, #a = :a"},
+		{"ADD #set :one", "SET #updatedAt = :updatedAt ADD #set :one"},
+		{"ADD #n :set", "SET #updatedAt = :updatedAt ADD #n :set"},
+		{"DELETE #Set :vals", "SET #updatedAt = :updatedAt DELETE #Set :vals"},
+		{"ADD #reset :one SET #a = :set", "ADD #reset :one SET #updatedAt = :updatedAt, #a = :set"},
+	}
+
+	for _, tt := range tests {
+		got, names, values := ddb.stampUpdateExpression(tt.in, map[string]string{"#a": "a"}, nil)
+		if *got != tt.want {
+			t.Errorf("stampUpdateExpression(%q) = %q, want %q", tt.in, *got, tt.want)
+		}
+		if names["#updatedAt"] != "updated" || names["#a"] != "a" || values[":updatedAt"] == nil {
+			t.Errorf("stampUpdateExpression(%q) placeholders = %v, %v", tt.in, names, values)
+		}
+	}
+
+	got, names, values := (&DDBTable{}).stampUpdateExpression("ADD #n :one", nil, nil)
+	if *got != "ADD #n :one" || names != nil || values != nil {
+		t.Errorf("stampUpdateExpression without timestamps = %q, %v, %v", *got, names, values)
+	}
+}
@@ -0,0 +1,82 @@
+package go_dynamodb_wrapper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+// Loading the SDK config is comparatively slow, so tables created for the same
+// region and endpoint share their client. Tables with custom SDK config options
+// (HTTP client, ...) always get their own client, since those can't be compared.
+var clientCache = struct {
+	sync.Mutex
+	clients map[clientCacheKey]cachedClient
+}{clients: make(map[clientCacheKey]cachedClient)}
+
+type clientCacheKey struct {
+	region   string
+	endpoint string
+}
+
+type cachedClient struct {
+	cfg    aws.Config
+	client *dynamodb.Client
+}
+
+////////////////////////
+// Internal functions //
+////////////////////////
+
+func (ddb *DDBTable) newClient(region string) (cachedClient, error) {
+	if len(ddb.configOptions) > 0 {
+		return ddb.loadClient(region)
+	}
+
+	key := clientCacheKey{region: region, endpoint: ddb.endpoint}
+
+	clientCache.Lock()
+	defer clientCache.Unlock()
+
+	if cached, ok := clientCache.clients[key]; ok {
+		return cached, nil
+	}
+
+	cached, err := ddb.loadClient(region)
+	if err != nil {
+		return cachedClient{}, err
+	}
+	clientCache.clients[key] = cached
+
+	return cached, nil
+}
+
+func (ddb *DDBTable) loadClient(region string) (cachedClient, error) {
+	// Create DynamoDB client, an empty region is resolved from the environment (e.g. AWS_REGION)
+	var loadOptions []func(*config.LoadOptions) error
+	if region != "" {
+		loadOptions = append(loadOptions, config.WithRegion(region))
+	}
+	loadOptions = append(loadOptions, ddb.configOptions...)
+
+	cfg, err := config.LoadDefaultConfig(context.Background(), loadOptions...)
+	if err != nil {
+		return cachedClient{}, fmt.Errorf("unable to load AWS SDK config: %v", err)
+	}
+	if cfg.Region == "" {
+		return cachedClient{}, errors.New("no region specified and none could be resolved from the environment")
+	}
+
+	client := dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
+		if ddb.endpoint != "" {
+			o.BaseEndpoint = aws.String(ddb.endpoint)
+		}
+	})
+
+	return cachedClient{cfg: cfg, client: client}, nil
+}
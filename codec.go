@@ -0,0 +1,167 @@
+package go_dynamodb_wrapper
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// AttributeCodec transforms the content of an attribute on its way to and from
+// the table, see WithAttributeCodec.
+type AttributeCodec interface {
+	Encode(data []byte) ([]byte, error)
+	Decode(data []byte) ([]byte, error)
+}
+
+// GzipCodec compresses attributes with gzip.
+var GzipCodec AttributeCodec = gzipCodec{}
+
+type gzipCodec struct{}
+
+func (gzipCodec) Encode(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gzipCodec) Decode(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return io.ReadAll(r)
+}
+
+// ValueCodec serializes attribute values in a format of the caller's choice, such
+// as protobuf or msgpack, see WithValueCodec.
+type ValueCodec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// WriteItemCodec writes item like WriteItem, after serializing the attributes
+// that have a ValueCodec into binary attributes.
+func (ddb *DDBTable) WriteItemCodec(ctx context.Context, item map[string]interface{}) error {
+	encoded := make(map[string]interface{}, len(item))
+	for k, v := range item {
+		if codec, ok := ddb.valueCodecs[k]; ok {
+			data, err := codec.Marshal(v)
+			if err != nil {
+				return fmt.Errorf("failed to marshal attribute %q: %w", k, err)
+			}
+			v = data
+		}
+		encoded[k] = v
+	}
+
+	dynamodbItem, err := ddb.marshalMap(encoded)
+	if err != nil {
+		return err
+	}
+	if err := ddb.prepareItem(dynamodbItem); err != nil {
+		return err
+	}
+
+	_, err = ddb.putItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(ddb.name),
+		Item:      dynamodbItem,
+	})
+	return err
+}
+
+// ReadItemCodec reads an item like ReadItem and deserializes the attributes that
+// have a ValueCodec into the pointers targets holds for them, keyed by attribute
+// name. Attributes without a target are left in the returned map as bytes.
+func (ddb *DDBTable) ReadItemCodec(ctx context.Context, partitionKeyValue string, targets map[string]interface{}) (map[string]interface{}, error) {
+	raw, err := ddb.ReadItemRaw(ctx, partitionKeyValue)
+	if err != nil {
+		return nil, err
+	}
+	item := ddb.convertItem(raw)
+
+	for k, target := range targets {
+		codec, ok := ddb.valueCodecs[k]
+		if !ok {
+			return nil, fmt.Errorf("attribute %q has no value codec, use WithValueCodec", k)
+		}
+		data, ok := item[k].([]byte)
+		if !ok {
+			continue
+		}
+		if err := codec.Unmarshal(data, target); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal attribute %q: %w", k, err)
+		}
+	}
+
+	return item, nil
+}
+
+////////////////////////
+// Internal functions //
+////////////////////////
+
+// encodeAttributes replaces the attributes that have a codec by their encoded
+// binary form.
+func (ddb *DDBTable) encodeAttributes(item map[string]types.AttributeValue) error {
+	for name, codec := range ddb.converter.codecs {
+		av, ok := item[name]
+		if !ok {
+			continue
+		}
+
+		var data []byte
+		switch v := av.(type) {
+		case *types.AttributeValueMemberS:
+			data = []byte(v.Value)
+		case *types.AttributeValueMemberB:
+			data = v.Value
+		default:
+			return fmt.Errorf("attribute %q has a codec and must be a string or binary, got %T", name, av)
+		}
+
+		encoded, err := codec.Encode(data)
+		if err != nil {
+			return fmt.Errorf("failed to encode attribute %q: %w", name, err)
+		}
+		item[name] = &types.AttributeValueMemberB{Value: encoded}
+	}
+
+	return nil
+}
+
+// decodedItem returns a copy of item with the attributes that have a codec
+// decoded, so that writing it back encodes them only once. Values that fail to
+// decode are kept as stored, like the map based reads do.
+func (ddb *DDBTable) decodedItem(item map[string]types.AttributeValue) map[string]types.AttributeValue {
+	if len(ddb.converter.codecs) == 0 {
+		return item
+	}
+
+	decoded := make(map[string]types.AttributeValue, len(item))
+	for name, av := range item {
+		if codec, ok := ddb.converter.codecs[name]; ok {
+			if b, ok := av.(*types.AttributeValueMemberB); ok {
+				if data, err := codec.Decode(b.Value); err == nil {
+					av = &types.AttributeValueMemberB{Value: data}
+				}
+			}
+		}
+		decoded[name] = av
+	}
+
+	return decoded
+}
@@ -0,0 +1,74 @@
+package go_dynamodb_wrapper
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+// TestCodecAttributesEncodedOnce goes through the paths that read items to
+// write them back, which must not encode codec attributes a second time.
+func TestCodecAttributesEncodedOnce(t *testing.T) {
+	ctx := context.Background()
+	body := []byte("hello")
+
+	readBody := func(t *testing.T, ddb *DDBTable) []byte {
+		t.Helper()
+		item, err := ddb.ReadItem("a")
+		if err != nil {
+			t.Fatalf("ReadItem: %v", err)
+		}
+		data, _ := item["body"].([]byte)
+		return data
+	}
+
+	t.Run("UpdateWithRetry", func(t *testing.T) {
+		ddb, _ := newFakeTable(t, WithAttributeCodec("body", GzipCodec))
+		if err := ddb.WriteItem(map[string]interface{}{"id": "a", "body": body}); err != nil {
+			t.Fatalf("WriteItem: %v", err)
+		}
+		err := ddb.UpdateWithRetry(ctx, "a", func(current map[string]interface{}) (map[string]interface{}, error) {
+			if !bytes.Equal(current["body"].([]byte), body) {
+				t.Errorf("mutate got body %q, want %q", current["body"], body)
+			}
+			current["n"] = 1
+			return current, nil
+		}, 0)
+		if err != nil {
+			t.Fatalf("UpdateWithRetry: %v", err)
+		}
+		if got := readBody(t, ddb); !bytes.Equal(got, body) {
+			t.Errorf("body = %q, want %q", got, body)
+		}
+	})
+
+	t.Run("CopyTo", func(t *testing.T) {
+		src, _ := newFakeTable(t, WithAttributeCodec("body", GzipCodec))
+		dest, _ := newFakeTable(t, WithAttributeCodec("body", GzipCodec))
+		if err := src.WriteItem(map[string]interface{}{"id": "a", "body": body}); err != nil {
+			t.Fatalf("WriteItem: %v", err)
+		}
+		if err := src.CopyTo(ctx, dest, nil); err != nil {
+			t.Fatalf("CopyTo: %v", err)
+		}
+		if got := readBody(t, dest); !bytes.Equal(got, body) {
+			t.Errorf("copied body = %q, want %q", got, body)
+		}
+	})
+
+	t.Run("MigrateItems", func(t *testing.T) {
+		ddb, _ := newFakeTable(t, WithAttributeCodec("body", GzipCodec))
+		if err := ddb.WriteItem(map[string]interface{}{"id": "a", "body": body}); err != nil {
+			t.Fatalf("WriteItem: %v", err)
+		}
+		err := ddb.MigrateItems(ctx, func(item map[string]interface{}) (map[string]interface{}, bool, error) {
+			if got, _ := item["body"].([]byte); !bytes.Equal(got, body) {
+				t.Errorf("fn got body %q, want %q", got, body)
+			}
+			return nil, true, nil
+		})
+		if err != nil {
+			t.Fatalf("MigrateItems: %v", err)
+		}
+	})
+}
@@ -0,0 +1,309 @@
+package go_dynamodb_wrapper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// PutWithVersion writes item using optimistic locking on versionAttr. The item's
+// version must be the one currently stored; it is stored incremented by one.
+// A missing or zero version means the stored item must not carry a version yet:
+// the item either doesn't exist or was written without one, in which case it
+// is overwritten. ErrConditionFailed is returned when someone else updated the
+// item in the meantime.
+func (ddb *DDBTable) PutWithVersion(ctx context.Context, item map[string]interface{}, versionAttr string) error {
+	if err := ddb.checkUnsharded("PutWithVersion"); err != nil {
+		return err
+	}
+	version, err := versionOf(item, versionAttr)
+	if err != nil {
+		return err
+	}
+
+	dynamodbItem, err := ddb.marshalMap(item)
+	if err != nil {
+		return err
+	}
+	dynamodbItem[versionAttr] = &types.AttributeValueMemberN{Value: strconv.FormatInt(version+1, 10)}
+	if err := ddb.prepareItem(dynamodbItem); err != nil {
+		return err
+	}
+
+	input := &dynamodb.PutItemInput{
+		TableName:                aws.String(ddb.name),
+		Item:                     dynamodbItem,
+		ExpressionAttributeNames: map[string]string{"#v": versionAttr},
+	}
+	if version == 0 {
+		input.ConditionExpression = aws.String("attribute_not_exists(#v)")
+	} else {
+		input.ConditionExpression = aws.String("#v = :v")
+		input.ExpressionAttributeValues = map[string]types.AttributeValue{
+			":v": &types.AttributeValueMemberN{Value: strconv.FormatInt(version, 10)},
+		}
+	}
+
+	_, err = ddb.putItem(ctx, input)
+	if isConditionalCheckFailed(err) {
+		return ErrConditionFailed
+	}
+
+	return err
+}
+
+// UpdateWithRetry runs a read-modify-write cycle on an item guarded by its
+// version attribute (see WithVersionAttribute): the item is read with a strongly
+// consistent read, passed to mutate (nil when it doesn't exist yet, its codec
+// attributes decoded otherwise) and the returned item is written with
+// PutWithVersion. When another writer got there first the whole cycle is
+// retried, up to maxRetries times, after which ErrConditionFailed is returned.
+func (ddb *DDBTable) UpdateWithRetry(ctx context.Context, partitionKeyValue string, mutate func(current map[string]interface{}) (map[string]interface{}, error), maxRetries int) error {
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		current, err := ddb.readConsistent(ctx, partitionKeyValue)
+		if err != nil {
+			return err
+		}
+
+		var version interface{}
+		if current != nil {
+			version = current[ddb.versionAttribute]
+		}
+
+		updated, err := mutate(current)
+		if err != nil {
+			return err
+		}
+		if updated == nil {
+			return errors.New("mutate returned no item")
+		}
+		updated[ddb.versionAttribute] = version
+
+		err = ddb.PutWithVersion(ctx, updated, ddb.versionAttribute)
+		if !errors.Is(err, ErrConditionFailed) {
+			return err
+		}
+	}
+
+	return ErrConditionFailed
+}
+
+// Condition is a ConditionExpression along with the attribute names and values
+// its #name and :value placeholders stand for, e.g. comparing two attributes:
+//
+//	Condition{
+//		Expression: "#new > #current",
+//		Names:      map[string]string{"#new": "new_value", "#current": "current_value"},
+//	}
+type Condition struct {
+	Expression string
+	Names      map[string]string
+	Values     map[string]interface{}
+}
+
+// WriteItemConditional writes item like WriteItem, provided the stored item
+// satisfies condition. ErrConditionFailed is returned otherwise.
+func (ddb *DDBTable) WriteItemConditional(ctx context.Context, item map[string]interface{}, condition Condition) error {
+	if err := ddb.checkUnsharded("WriteItemConditional"); err != nil {
+		return err
+	}
+	dynamodbItem, err := ddb.marshalMap(item)
+	if err != nil {
+		return err
+	}
+	if err := ddb.prepareItem(dynamodbItem); err != nil {
+		return err
+	}
+
+	input := &dynamodb.PutItemInput{
+		TableName: aws.String(ddb.name),
+		Item:      dynamodbItem,
+	}
+	input.ConditionExpression, input.ExpressionAttributeNames, input.ExpressionAttributeValues, err = condition.build(nil, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = ddb.putItem(ctx, input)
+	if isConditionalCheckFailed(err) {
+		return ErrConditionFailed
+	}
+
+	return err
+}
+
+// UpdateItemConditional updates the item like UpdateItem, provided it satisfies
+// condition. ErrConditionFailed is returned otherwise. The placeholders #k1,
+// :v1, #k2, ... are used for the updated attributes and must not appear in the
+// condition.
+func (ddb *DDBTable) UpdateItemConditional(ctx context.Context, partitionKeyValue string, updatedValue map[string]interface{}, condition Condition) error {
+	key, err := ddb.key(partitionKeyValue)
+	if err != nil {
+		return err
+	}
+
+	values, err := ddb.marshalMap(updatedValue)
+	if err != nil {
+		return err
+	}
+	if err := ddb.prepareUpdate(values); err != nil {
+		return err
+	}
+
+	input := ddb.setUpdateInput(key, values)
+	input.ConditionExpression, input.ExpressionAttributeNames, input.ExpressionAttributeValues, err = condition.build(input.ExpressionAttributeNames, input.ExpressionAttributeValues)
+	if err != nil {
+		return err
+	}
+
+	_, err = ddb.updateItem(ctx, input)
+	if isConditionalCheckFailed(err) {
+		return ErrConditionFailed
+	}
+
+	return err
+}
+
+// AcquireLock writes item as the record with the given partition key, unless
+// such a record exists already. When it does, acquired is false and current is
+// the existing record, as returned by the failed write itself, e.g. to find out
+// who holds the lock.
+func (ddb *DDBTable) AcquireLock(ctx context.Context, partitionKeyValue string, item map[string]interface{}) (bool, map[string]interface{}, error) {
+	if err := ddb.checkUnsharded("AcquireLock"); err != nil {
+		return false, nil, err
+	}
+	dynamodbItem, err := ddb.marshalMap(item)
+	if err != nil {
+		return false, nil, err
+	}
+	pk, err := ddb.partitionKeyAttributeValue(partitionKeyValue)
+	if err != nil {
+		return false, nil, err
+	}
+	dynamodbItem[ddb.partitionKeyName] = pk
+	if err := ddb.prepareItem(dynamodbItem); err != nil {
+		return false, nil, err
+	}
+
+	input := &dynamodb.PutItemInput{
+		TableName:                           aws.String(ddb.name),
+		Item:                                dynamodbItem,
+		ConditionExpression:                 aws.String("attribute_not_exists(#pk)"),
+		ExpressionAttributeNames:            map[string]string{"#pk": ddb.partitionKeyName},
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	}
+
+	_, err = ddb.putItem(ctx, input)
+	var ccf *types.ConditionalCheckFailedException
+	if errors.As(err, &ccf) {
+		return false, ddb.convertItem(ccf.Item), nil
+	}
+	if err != nil {
+		return false, nil, err
+	}
+
+	return true, nil, nil
+}
+
+// IncrementBounded atomically adds delta to the number attributeName of the item
+// with the given partition key, unless the result would exceed maxValue in which
+// case nothing is changed and ErrConditionFailed is returned. A missing
+// attribute counts as 0. This makes it suitable for enforcing quotas.
+func (ddb *DDBTable) IncrementBounded(ctx context.Context, partitionKeyValue, attributeName string, delta, maxValue float64) error {
+	key, err := ddb.key(partitionKeyValue)
+	if err != nil {
+		return err
+	}
+
+	condition := "#a <= :limit"
+	if delta <= maxValue {
+		condition = "attribute_not_exists(#a) OR " + condition
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName:                aws.String(ddb.name),
+		Key:                      key,
+		UpdateExpression:         aws.String("ADD #a :delta"),
+		ConditionExpression:      aws.String(condition),
+		ExpressionAttributeNames: map[string]string{"#a": attributeName},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":delta": &types.AttributeValueMemberN{Value: strconv.FormatFloat(delta, 'f', -1, 64)},
+			":limit": &types.AttributeValueMemberN{Value: strconv.FormatFloat(maxValue-delta, 'f', -1, 64)},
+		},
+	}
+	input.UpdateExpression, input.ExpressionAttributeNames, input.ExpressionAttributeValues = ddb.stampUpdateExpression(*input.UpdateExpression, input.ExpressionAttributeNames, input.ExpressionAttributeValues)
+
+	_, err = ddb.updateItem(ctx, input)
+	if isConditionalCheckFailed(err) {
+		return ErrConditionFailed
+	}
+
+	return err
+}
+
+////////////////////////
+// Internal functions //
+////////////////////////
+
+func versionOf(item map[string]interface{}, versionAttr string) (int64, error) {
+	value, ok := item[versionAttr]
+	if !ok || value == nil {
+		return 0, nil
+	}
+
+	version, err := strconv.ParseInt(fmt.Sprintf("%v", value), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("version attribute %q is not an integer: %v", versionAttr, value)
+	}
+
+	return version, nil
+}
+
+// build returns the request fields of the condition, merging its placeholders
+// with those the request already uses.
+func (c Condition) build(names map[string]string, values map[string]types.AttributeValue) (*string, map[string]string, map[string]types.AttributeValue, error) {
+	names = mergeMaps(names, c.Names)
+
+	if len(c.Values) > 0 {
+		conditionValues, err := MarshalMap(c.Values)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		values = mergeMaps(values, conditionValues)
+	}
+
+	return aws.String(c.Expression), names, values, nil
+}
+
+func isConditionalCheckFailed(err error) bool {
+	var ccf *types.ConditionalCheckFailedException
+	return errors.As(err, &ccf)
+}
+
+// readConsistent reads an item without losing type information, returning nil
+// when the item doesn't exist. Codec attributes come back decoded.
+func (ddb *DDBTable) readConsistent(ctx context.Context, partitionKeyValue string) (map[string]interface{}, error) {
+	key, err := ddb.key(partitionKeyValue)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := ddb.getItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(ddb.name),
+		Key:            key,
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Item) == 0 {
+		return nil, nil
+	}
+
+	return UnmarshalMap(ddb.decodedItem(result.Item))
+}
@@ -0,0 +1,224 @@
+package go_dynamodb_wrapper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// N and S force the attribute type of a value in writes and expression values,
+// overriding the inference from its Go type: N("123") is stored as a number and
+// S("123") as a string.
+type (
+	N string
+	S string
+)
+
+// MarshalMap converts a plain Go map into DynamoDB attribute values. Supported
+// values are strings, bools, integers, floats, json.Number (stored as N without
+// reformatting), the N and S type hints, []byte, nil, nested
+// map[string]interface{} / []interface{} and []string / []json.Number /
+// [][]byte as well as StringSet / NumberSet / BinarySet for the set types. Sets
+// can't be empty in DynamoDB, so empty ones are rejected.
+// Structs, or pointers to them, are marshaled with the attributevalue package
+// and honour its dynamodbav struct tags. The table writes marshal them with the
+// table's WithEncoderOptions.
+func MarshalMap(item map[string]interface{}) (map[string]types.AttributeValue, error) {
+	return marshalMap(item, nil)
+}
+
+// UnmarshalMap converts DynamoDB attribute values back into a plain Go map
+// without losing information: numbers become json.Number, so that MarshalMap
+// on the result yields the original attribute values.
+func UnmarshalMap(item map[string]types.AttributeValue) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(item))
+	for k, av := range item {
+		v, err := unmarshalValue(av)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %q: %w", k, err)
+		}
+		result[k] = v
+	}
+	return result, nil
+}
+
+////////////////////////
+// Internal functions //
+////////////////////////
+
+func (ddb *DDBTable) marshalMap(item map[string]interface{}) (map[string]types.AttributeValue, error) {
+	return marshalMap(item, ddb.encoderOptions)
+}
+
+func marshalMap(item map[string]interface{}, encoderOptions []func(*attributevalue.EncoderOptions)) (map[string]types.AttributeValue, error) {
+	result := make(map[string]types.AttributeValue, len(item))
+	for k, v := range item {
+		av, err := marshalValueWithOptions(v, encoderOptions)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %q: %w", k, err)
+		}
+		result[k] = av
+	}
+	return result, nil
+}
+
+func marshalValue(value interface{}) (types.AttributeValue, error) {
+	return marshalValueWithOptions(value, nil)
+}
+
+// marshalValueWithOptions is marshalValue marshaling structs with the given
+// encoder options.
+func marshalValueWithOptions(value interface{}, encoderOptions []func(*attributevalue.EncoderOptions)) (types.AttributeValue, error) {
+	if n, ok := setLen(value); ok && n == 0 {
+		return nil, errors.New("empty sets are not supported")
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return &types.AttributeValueMemberNULL{Value: true}, nil
+	case string:
+		return &types.AttributeValueMemberS{Value: v}, nil
+	case bool:
+		return &types.AttributeValueMemberBOOL{Value: v}, nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", v)}, nil
+	case float32:
+		return &types.AttributeValueMemberN{Value: strconv.FormatFloat(float64(v), 'f', -1, 32)}, nil
+	case float64:
+		return &types.AttributeValueMemberN{Value: strconv.FormatFloat(v, 'f', -1, 64)}, nil
+	case json.Number:
+		return &types.AttributeValueMemberN{Value: v.String()}, nil
+	case N:
+		if !isNumber(string(v)) {
+			return nil, fmt.Errorf("%q is not a number", string(v))
+		}
+		return &types.AttributeValueMemberN{Value: string(v)}, nil
+	case S:
+		return &types.AttributeValueMemberS{Value: string(v)}, nil
+	case []byte:
+		return &types.AttributeValueMemberB{Value: v}, nil
+	case map[string]interface{}:
+		m, err := marshalMap(v, encoderOptions)
+		if err != nil {
+			return nil, err
+		}
+		return &types.AttributeValueMemberM{Value: m}, nil
+	case []interface{}:
+		listValues := make([]types.AttributeValue, 0, len(v))
+		for _, item := range v {
+			av, err := marshalValueWithOptions(item, encoderOptions)
+			if err != nil {
+				return nil, err
+			}
+			listValues = append(listValues, av)
+		}
+		return &types.AttributeValueMemberL{Value: listValues}, nil
+	case []string:
+		return &types.AttributeValueMemberSS{Value: v}, nil
+	case []json.Number:
+		numbers := make([]string, len(v))
+		for i, n := range v {
+			numbers[i] = n.String()
+		}
+		return numberSet(numbers)
+	case [][]byte:
+		return &types.AttributeValueMemberBS{Value: v}, nil
+	case StringSet:
+		return &types.AttributeValueMemberSS{Value: v}, nil
+	case NumberSet:
+		return numberSet(v)
+	case BinarySet:
+		return &types.AttributeValueMemberBS{Value: v}, nil
+	default:
+		if isStruct(v) {
+			return attributevalue.MarshalWithOptions(v, encoderOptions...)
+		}
+		return nil, fmt.Errorf("unsupported type: %v", reflect.TypeOf(v))
+	}
+}
+
+// setLen returns the number of elements of the values stored as sets, ok is
+// false for any other value.
+func setLen(value interface{}) (n int, ok bool) {
+	switch v := value.(type) {
+	case []string:
+		return len(v), true
+	case []json.Number:
+		return len(v), true
+	case [][]byte:
+		return len(v), true
+	case StringSet:
+		return len(v), true
+	case NumberSet:
+		return len(v), true
+	case BinarySet:
+		return len(v), true
+	default:
+		return 0, false
+	}
+}
+
+func numberSet(numbers []string) (types.AttributeValue, error) {
+	for _, n := range numbers {
+		if !isNumber(n) {
+			return nil, fmt.Errorf("%q is not a number", n)
+		}
+	}
+	return &types.AttributeValueMemberNS{Value: numbers}, nil
+}
+
+// isStruct reports whether value is a struct or a non-nil pointer to one.
+func isStruct(value interface{}) bool {
+	t := reflect.TypeOf(value)
+	if t.Kind() == reflect.Pointer {
+		if reflect.ValueOf(value).IsNil() {
+			return false
+		}
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+func unmarshalValue(av types.AttributeValue) (interface{}, error) {
+	switch val := av.(type) {
+	case *types.AttributeValueMemberS:
+		return val.Value, nil
+	case *types.AttributeValueMemberN:
+		return json.Number(val.Value), nil
+	case *types.AttributeValueMemberBOOL:
+		return val.Value, nil
+	case *types.AttributeValueMemberNULL:
+		return nil, nil
+	case *types.AttributeValueMemberB:
+		return val.Value, nil
+	case *types.AttributeValueMemberM:
+		return UnmarshalMap(val.Value)
+	case *types.AttributeValueMemberL:
+		list := make([]interface{}, 0, len(val.Value))
+		for _, item := range val.Value {
+			v, err := unmarshalValue(item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, nil
+	case *types.AttributeValueMemberSS:
+		return val.Value, nil
+	case *types.AttributeValueMemberNS:
+		numbers := make([]json.Number, len(val.Value))
+		for i, n := range val.Value {
+			numbers[i] = json.Number(n)
+		}
+		return numbers, nil
+	case *types.AttributeValueMemberBS:
+		return val.Value, nil
+	default:
+		return nil, fmt.Errorf("unsupported attribute value type: %T", av)
+	}
+}
@@ -0,0 +1,173 @@
+package go_dynamodb_wrapper
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestParseNumberBeyondFloatPrecision(t *testing.T) {
+	tests := []struct {
+		in   string
+		want interface{}
+	}{
+		{"9007199254740992", int64(9007199254740992)},
+		{"9007199254740993", int64(9007199254740993)},
+		{"-9007199254740993", int64(-9007199254740993)},
+		{"9223372036854775807", int64(9223372036854775807)},
+		{"9223372036854775808", "9223372036854775808"},
+		{"18446744073709551616", "18446744073709551616"},
+		{"9007199254740993.0", "9007199254740993.0"},
+		{"1.5", 1.5},
+	}
+
+	for _, tt := range tests {
+		if got := parseNumber(tt.in); got != tt.want {
+			t.Errorf("parseNumber(%q) = %#v, want %#v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNumberParsingRoundTripsLargeIntegers(t *testing.T) {
+	ddb := &DDBTable{}
+	WithNumberParsing()(ddb)
+
+	item := map[string]interface{}{
+		"above2to53": int64(9007199254740993),
+		"maxInt64":   int64(9223372036854775807),
+		"maxUint64":  uint64(18446744073709551615),
+	}
+	dynamodbItem, err := MarshalMap(item)
+	if err != nil {
+		t.Fatalf("MarshalMap: %v", err)
+	}
+
+	if n := dynamodbItem["above2to53"].(*types.AttributeValueMemberN).Value; n != "9007199254740993" {
+		t.Errorf("above2to53 stored as %q", n)
+	}
+
+	got := ddb.convertItem(dynamodbItem)
+	want := map[string]interface{}{
+		"above2to53": int64(9007199254740993),
+		"maxInt64":   int64(9223372036854775807),
+		"maxUint64":  "18446744073709551615",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("convertItem = %#v, want %#v", got, want)
+	}
+}
+
+func TestMarshalFloatsInPlainNotation(t *testing.T) {
+	tests := []struct {
+		in   interface{}
+		want string
+	}{
+		{1e21, "1000000000000000000000"},
+		{-1.5e22, "-15000000000000000000000"},
+		{1e-7, "0.0000001"},
+		{-2.5e-10, "-0.00000000025"},
+		{float32(1e21), "1000000000000000000000"},
+		{float32(1e-7), "0.0000001"},
+		{float32(0.1), "0.1"},
+	}
+
+	for _, tt := range tests {
+		av, err := marshalValue(tt.in)
+		if err != nil {
+			t.Fatalf("marshalValue(%v): %v", tt.in, err)
+		}
+		if got := av.(*types.AttributeValueMemberN).Value; got != tt.want {
+			t.Errorf("marshalValue(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMarshalExtremeFloatsRoundTrip(t *testing.T) {
+	for _, f := range []float64{1e125, 9.999999999999999e125, 1e-100, 1.2345678901234567e-120, 123456789.125} {
+		av, err := marshalValue(f)
+		if err != nil {
+			t.Fatalf("marshalValue(%v): %v", f, err)
+		}
+		n := av.(*types.AttributeValueMemberN).Value
+		if strings.ContainsAny(n, "eE") {
+			t.Errorf("marshalValue(%v) = %q, want plain decimal notation", f, n)
+		}
+		if parsed, err := strconv.ParseFloat(n, 64); err != nil || parsed != f {
+			t.Errorf("marshalValue(%v) = %q, which parses back as %v (%v)", f, n, parsed, err)
+		}
+	}
+}
+
+func TestNestedListMapListRoundTrip(t *testing.T) {
+	item := map[string]interface{}{
+		"orders": []interface{}{
+			map[string]interface{}{
+				"lines": []interface{}{"book", 2, []interface{}{true, nil}},
+			},
+		},
+	}
+
+	dynamodbItem, err := MarshalMap(item)
+	if err != nil {
+		t.Fatalf("MarshalMap: %v", err)
+	}
+
+	wantAV := &types.AttributeValueMemberL{Value: []types.AttributeValue{
+		&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			"lines": &types.AttributeValueMemberL{Value: []types.AttributeValue{
+				&types.AttributeValueMemberS{Value: "book"},
+				&types.AttributeValueMemberN{Value: "2"},
+				&types.AttributeValueMemberL{Value: []types.AttributeValue{
+					&types.AttributeValueMemberBOOL{Value: true},
+					&types.AttributeValueMemberNULL{Value: true},
+				}},
+			}},
+		}},
+	}}
+	if !reflect.DeepEqual(dynamodbItem["orders"], wantAV) {
+		t.Errorf("MarshalMap = %#v, want %#v", dynamodbItem["orders"], wantAV)
+	}
+
+	got := convertDynamoDBJSONToMap(dynamodbItem)
+	want := map[string]interface{}{
+		"orders": []interface{}{
+			map[string]interface{}{
+				"lines": []interface{}{"book", "2", []interface{}{"true", nil}},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("convertDynamoDBJSONToMap = %#v, want %#v", got, want)
+	}
+}
+
+func TestMarshalSets(t *testing.T) {
+	tests := []struct {
+		in      interface{}
+		wantErr bool
+	}{
+		{[]string{"a"}, false},
+		{StringSet{"a"}, false},
+		{NumberSet{"1", "2.5"}, false},
+		{[]json.Number{"-3"}, false},
+		{[][]byte{{1}}, false},
+		{[]string{}, true},
+		{StringSet{}, true},
+		{NumberSet{}, true},
+		{BinarySet{}, true},
+		{[]json.Number{}, true},
+		{[][]byte{}, true},
+		{NumberSet{"1", "one"}, true},
+		{[]json.Number{"1e"}, true},
+	}
+
+	for _, tt := range tests {
+		if _, err := marshalValue(tt.in); (err != nil) != tt.wantErr {
+			t.Errorf("marshalValue(%#v) error = %v, want error %v", tt.in, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package go_dynamodb_wrapper
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// cursorAttribute is the JSON form of a key attribute, keys only ever hold
+// strings, numbers or binary values.
+type cursorAttribute struct {
+	S *string `json:"S,omitempty"`
+	N *string `json:"N,omitempty"`
+	B []byte  `json:"B,omitempty"`
+}
+
+// EncodeCursor turns a LastEvaluatedKey into an opaque, URL safe string that can
+// be handed to clients, e.g. as a query parameter. A nil key encodes to "".
+func EncodeCursor(key map[string]types.AttributeValue) (string, error) {
+	if key == nil {
+		return "", nil
+	}
+
+	attributes := make(map[string]cursorAttribute, len(key))
+	for name, av := range key {
+		switch v := av.(type) {
+		case *types.AttributeValueMemberS:
+			attributes[name] = cursorAttribute{S: &v.Value}
+		case *types.AttributeValueMemberN:
+			attributes[name] = cursorAttribute{N: &v.Value}
+		case *types.AttributeValueMemberB:
+			attributes[name] = cursorAttribute{B: v.Value}
+		default:
+			return "", fmt.Errorf("unsupported key attribute type for %q: %T", name, av)
+		}
+	}
+
+	data, err := json.Marshal(attributes)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor turns a string produced by EncodeCursor back into a key usable as
+// ExclusiveStartKey. An empty cursor decodes to a nil key.
+func DecodeCursor(cursor string) (map[string]types.AttributeValue, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	var attributes map[string]cursorAttribute
+	if err := json.Unmarshal(data, &attributes); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	key := make(map[string]types.AttributeValue, len(attributes))
+	for name, a := range attributes {
+		switch {
+		case a.S != nil:
+			key[name] = &types.AttributeValueMemberS{Value: *a.S}
+		case a.N != nil:
+			key[name] = &types.AttributeValueMemberN{Value: *a.N}
+		case a.B != nil:
+			key[name] = &types.AttributeValueMemberB{Value: a.B}
+		default:
+			return nil, fmt.Errorf("invalid cursor: attribute %q has no value", name)
+		}
+	}
+
+	return key, nil
+}
@@ -0,0 +1,118 @@
+package go_dynamodb_wrapper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+// ReadItemDecimal reads an item like UnmarshalMap would, except that every number
+// (including those nested in maps, lists and number sets) is returned as an
+// exact *big.Rat, so monetary values never go through float64.
+func (ddb *DDBTable) ReadItemDecimal(ctx context.Context, partitionKeyValue string) (map[string]interface{}, error) {
+	key, err := ddb.key(partitionKeyValue)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &dynamodb.GetItemInput{
+		TableName:      aws.String(ddb.name),
+		Key:            key,
+		ConsistentRead: ddb.consistentRead(nil),
+	}
+
+	result, err := ddb.getItem(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Item) == 0 {
+		return nil, ErrItemNotFound
+	}
+
+	item, err := UnmarshalMap(result.Item)
+	if err != nil {
+		return nil, err
+	}
+
+	decimals, err := toDecimal(item)
+	if err != nil {
+		return nil, err
+	}
+
+	return decimals.(map[string]interface{}), nil
+}
+
+////////////////////////
+// Internal functions //
+////////////////////////
+
+func toDecimal(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case json.Number:
+		return parseRat(v.String())
+	case []json.Number:
+		rats := make([]*big.Rat, len(v))
+		for i, n := range v {
+			r, err := parseRat(n.String())
+			if err != nil {
+				return nil, err
+			}
+			rats[i] = r
+		}
+		return rats, nil
+	case map[string]interface{}:
+		for k, item := range v {
+			d, err := toDecimal(item)
+			if err != nil {
+				return nil, err
+			}
+			v[k] = d
+		}
+		return v, nil
+	case []interface{}:
+		for i, item := range v {
+			d, err := toDecimal(item)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = d
+		}
+		return v, nil
+	default:
+		return v, nil
+	}
+}
+
+func parseRat(s string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, fmt.Errorf("invalid number: %q", s)
+	}
+	return r, nil
+}
+
+// canonicalNumber formats a number in plain decimal notation with no exponent and
+// no superfluous zeros, e.g. "1e+06" becomes "1000000" and "2.50" becomes "2.5".
+func canonicalNumber(s string) (string, error) {
+	r, err := parseRat(s)
+	if err != nil {
+		return "", err
+	}
+	if r.IsInt() {
+		return r.Num().String(), nil
+	}
+
+	// The number came from a decimal string, so some precision represents it
+	// exactly; DynamoDB numbers have at most 38 digits and exponents down to -130.
+	for prec := 1; prec <= 200; prec++ {
+		f := r.FloatString(prec)
+		if exact, _ := new(big.Rat).SetString(f); exact.Cmp(r) == 0 {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("number %q has no exact decimal form", s)
+}
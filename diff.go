@@ -0,0 +1,136 @@
+package go_dynamodb_wrapper
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// EqualItems reports whether two items, as returned by the map based read
+// methods or UnmarshalMap, hold the same attributes. Nested maps and lists are
+// compared deeply and sets regardless of the order of their elements.
+func EqualItems(a, b map[string]interface{}) bool {
+	return equalValues(a, b)
+}
+
+// DiffItems compares the attributes of a and b: added holds those only b has,
+// removed those only a has and changed the new value (from b) of those whose
+// value differs, as decided by EqualItems.
+func DiffItems(a, b map[string]interface{}) (added, removed, changed map[string]interface{}) {
+	added = make(map[string]interface{})
+	removed = make(map[string]interface{})
+	changed = make(map[string]interface{})
+
+	for k, av := range a {
+		bv, ok := b[k]
+		if !ok {
+			removed[k] = av
+		} else if !equalValues(av, bv) {
+			changed[k] = bv
+		}
+	}
+	for k, bv := range b {
+		if _, ok := a[k]; !ok {
+			added[k] = bv
+		}
+	}
+
+	return added, removed, changed
+}
+
+////////////////////////
+// Internal functions //
+////////////////////////
+
+func equalValues(a, b interface{}) bool {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, v := range av {
+			w, ok := bv[k]
+			if !ok || !equalValues(v, w) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !equalValues(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	case []byte:
+		bv, ok := b.([]byte)
+		return ok && bytes.Equal(av, bv)
+	case StringSet:
+		bv, ok := b.(StringSet)
+		return ok && equalStringSets(av, bv)
+	case NumberSet:
+		bv, ok := b.(NumberSet)
+		return ok && equalStringSets(av, bv)
+	case []string:
+		bv, ok := b.([]string)
+		return ok && equalStringSets(av, bv)
+	case []json.Number:
+		bv, ok := b.([]json.Number)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		as, bs := make([]string, len(av)), make([]string, len(bv))
+		for i := range av {
+			as[i], bs[i] = av[i].String(), bv[i].String()
+		}
+		return equalStringSets(as, bs)
+	case BinarySet:
+		bv, ok := b.(BinarySet)
+		return ok && equalBinarySets(av, bv)
+	case [][]byte:
+		bv, ok := b.([][]byte)
+		return ok && equalBinarySets(av, bv)
+	default:
+		return reflect.DeepEqual(a, b)
+	}
+}
+
+func equalStringSets(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	as := append([]string(nil), a...)
+	bs := append([]string(nil), b...)
+	sort.Strings(as)
+	sort.Strings(bs)
+	for i := range as {
+		if as[i] != bs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalBinarySets(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	as := append([][]byte(nil), a...)
+	bs := append([][]byte(nil), b...)
+	sort.Slice(as, func(i, j int) bool { return bytes.Compare(as[i], as[j]) < 0 })
+	sort.Slice(bs, func(i, j int) bool { return bytes.Compare(bs[i], bs[j]) < 0 })
+	for i := range as {
+		if !bytes.Equal(as[i], bs[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+package go_dynamodb_wrapper
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrItemNotFound         = errors.New("item not found")
+	ErrAttributeNotFound    = errors.New("attribute not found")
+	ErrConditionFailed      = errors.New("condition check failed")
+	ErrInvalidTTL           = errors.New("invalid TTL")
+	ErrKeyTypeMismatch      = errors.New("key type mismatch")
+	ErrMultipleItemsFound   = errors.New("more than one item found")
+	ErrTableNotFound        = errors.New("table not found")
+	ErrSortKeyNotConfigured = errors.New("table has no sort key configured, use WithSortKey")
+	ErrSchemaMismatch       = errors.New("item does not match schema")
+	ErrItemTooLarge         = errors.New("item exceeds the 400KB size limit")
+)
+
+// DynamoError is returned by all operations when a DynamoDB API call fails. Op
+// is the name of the API operation, e.g. "PutItem". It unwraps to the SDK error,
+// so errors.As still finds the SDK exception types.
+type DynamoError struct {
+	Op    string
+	Table string
+	Err   error
+}
+
+func (e *DynamoError) Error() string {
+	return fmt.Sprintf("%s on table %q: %v", e.Op, e.Table, e.Err)
+}
+
+func (e *DynamoError) Unwrap() error {
+	return e.Err
+}
+
+// TableNotFoundError is wrapped by all operations when the table does not exist
+// (or is not visible with the current credentials and region). It matches
+// ErrTableNotFound with errors.Is and unwraps to the SDK's ResourceNotFoundException.
+type TableNotFoundError struct {
+	Table string
+	Err   error
+}
+
+func (e *TableNotFoundError) Error() string {
+	return fmt.Sprintf("table %q not found: %v", e.Table, e.Err)
+}
+
+func (e *TableNotFoundError) Is(target error) bool {
+	return target == ErrTableNotFound
+}
+
+func (e *TableNotFoundError) Unwrap() error {
+	return e.Err
+}
+
+// UnprocessedKeysError lists the keys a batch read gave up on after retrying, so
+// that they can be retried later.
+type UnprocessedKeysError struct {
+	PartitionKeyValues []string
+}
+
+func (e *UnprocessedKeysError) Error() string {
+	return fmt.Sprintf("%d keys still unprocessed after %d attempts", len(e.PartitionKeyValues), batchMaxAttempts)
+}
+
+// CancellationReason explains why one op of a canceled transaction failed. Code
+// is "None" for the ops that were not the cause, and e.g.
+// "ConditionalCheckFailed" for those whose condition did not hold.
+type CancellationReason struct {
+	Code    string
+	Message string
+}
+
+// TransactionCanceledError is returned when a transaction is canceled. Reasons
+// holds one entry per op, in order. It matches ErrConditionFailed with errors.Is
+// when any of its conditions failed.
+type TransactionCanceledError struct {
+	Reasons []CancellationReason
+	Err     error
+}
+
+func (e *TransactionCanceledError) Error() string {
+	return fmt.Sprintf("transaction canceled: %v", e.Err)
+}
+
+func (e *TransactionCanceledError) Is(target error) bool {
+	if target != ErrConditionFailed {
+		return false
+	}
+	for _, r := range e.Reasons {
+		if r.Code == "ConditionalCheckFailed" {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *TransactionCanceledError) Unwrap() error {
+	return e.Err
+}
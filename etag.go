@@ -0,0 +1,110 @@
+package go_dynamodb_wrapper
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ReadItemWithVersion reads an item along with a version string derived from its
+// content, usable as an HTTP ETag: it only changes when the stored item does.
+func (ddb *DDBTable) ReadItemWithVersion(ctx context.Context, partitionKeyValue string) (map[string]interface{}, string, error) {
+	key, err := ddb.key(partitionKeyValue)
+	if err != nil {
+		return nil, "", err
+	}
+
+	input := &dynamodb.GetItemInput{
+		TableName:      aws.String(ddb.name),
+		Key:            key,
+		ConsistentRead: ddb.consistentRead(nil),
+	}
+
+	result, err := ddb.getItem(ctx, input)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(result.Item) == 0 {
+		return nil, "", ErrItemNotFound
+	}
+
+	return ddb.convertItem(result.Item), ItemHash(result.Item), nil
+}
+
+// ItemHash returns a hex encoded SHA-256 of the item. Map keys and set elements
+// are sorted first, so equal items always hash the same.
+func ItemHash(item map[string]types.AttributeValue) string {
+	h := sha256.New()
+	hashMap(h, item)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+////////////////////////
+// Internal functions //
+////////////////////////
+
+func hashMap(h hash.Hash, m map[string]types.AttributeValue) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fmt.Fprintf(h, "M%d{", len(keys))
+	for _, k := range keys {
+		fmt.Fprintf(h, "%d:%s", len(k), k)
+		hashValue(h, m[k])
+	}
+	fmt.Fprint(h, "}")
+}
+
+func hashValue(h hash.Hash, av types.AttributeValue) {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberS:
+		fmt.Fprintf(h, "S%d:%s", len(v.Value), v.Value)
+	case *types.AttributeValueMemberN:
+		fmt.Fprintf(h, "N%d:%s", len(v.Value), v.Value)
+	case *types.AttributeValueMemberB:
+		fmt.Fprintf(h, "B%d:%s", len(v.Value), v.Value)
+	case *types.AttributeValueMemberBOOL:
+		fmt.Fprintf(h, "BOOL:%t", v.Value)
+	case *types.AttributeValueMemberNULL:
+		fmt.Fprint(h, "NULL")
+	case *types.AttributeValueMemberM:
+		hashMap(h, v.Value)
+	case *types.AttributeValueMemberL:
+		fmt.Fprintf(h, "L%d[", len(v.Value))
+		for _, item := range v.Value {
+			hashValue(h, item)
+		}
+		fmt.Fprint(h, "]")
+	case *types.AttributeValueMemberSS:
+		hashSet(h, "SS", v.Value)
+	case *types.AttributeValueMemberNS:
+		hashSet(h, "NS", v.Value)
+	case *types.AttributeValueMemberBS:
+		elements := make([]string, len(v.Value))
+		for i, b := range v.Value {
+			elements[i] = string(b)
+		}
+		hashSet(h, "BS", elements)
+	}
+}
+
+func hashSet(h hash.Hash, setType string, elements []string) {
+	sorted := append([]string(nil), elements...)
+	sort.Strings(sorted)
+
+	fmt.Fprintf(h, "%s%d[", setType, len(sorted))
+	for _, e := range sorted {
+		fmt.Fprintf(h, "%d:%s", len(e), e)
+	}
+	fmt.Fprint(h, "]")
+}
@@ -0,0 +1,115 @@
+package go_dynamodb_wrapper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ExportJSONL scans the whole table and writes every item to w as one JSON
+// object per line. Numbers are written exactly as stored and binary values as
+// base64. Map keys always come out sorted; with SortMapKeys set elements are
+// sorted too, making the output of an unchanged table identical across runs.
+func (ddb *DDBTable) ExportJSONL(ctx context.Context, w io.Writer, opts ...ExportOption) (int, error) {
+	options := newExportOptions(opts)
+	input := ddb.scanInput(newScanOptions(nil))
+	encoder := json.NewEncoder(w)
+
+	pages := NewPaginator(ddb.scanPages(input), func(item map[string]types.AttributeValue) (map[string]interface{}, error) {
+		if options.sortMapKeys {
+			item = sortSets(item)
+		}
+		return UnmarshalMap(item)
+	})
+
+	exported := 0
+	for pages.HasMore() {
+		items, err := pages.NextPage(ctx)
+		if err != nil {
+			return exported, err
+		}
+
+		for _, value := range items {
+			if err := encoder.Encode(value); err != nil {
+				return exported, err
+			}
+			exported++
+		}
+	}
+
+	return exported, nil
+}
+
+// ExportToS3 starts a native export of the table to s3Bucket under s3Prefix, in
+// DynamoDB JSON format, and returns the export's ARN. Unlike ExportJSONL it
+// consumes no read capacity but requires point in time recovery to be enabled.
+// The export runs asynchronously.
+func (ddb *DDBTable) ExportToS3(ctx context.Context, s3Bucket, s3Prefix string) (string, error) {
+	description, err := ddb.describeTable(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	input := &dynamodb.ExportTableToPointInTimeInput{
+		TableArn:     description.TableArn,
+		S3Bucket:     aws.String(s3Bucket),
+		ExportFormat: types.ExportFormatDynamodbJson,
+	}
+	if s3Prefix != "" {
+		input.S3Prefix = aws.String(s3Prefix)
+	}
+
+	result, err := ddb.exportTableToPointInTime(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return aws.ToString(result.ExportDescription.ExportArn), nil
+}
+
+////////////////////////
+// Internal functions //
+////////////////////////
+
+// sortSets returns a copy of item whose sets, at any nesting level, have their
+// elements in ascending order.
+func sortSets(item map[string]types.AttributeValue) map[string]types.AttributeValue {
+	result := make(map[string]types.AttributeValue, len(item))
+	for k, v := range item {
+		result[k] = sortSetValue(v)
+	}
+	return result
+}
+
+func sortSetValue(av types.AttributeValue) types.AttributeValue {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberM:
+		return &types.AttributeValueMemberM{Value: sortSets(v.Value)}
+	case *types.AttributeValueMemberL:
+		list := make([]types.AttributeValue, len(v.Value))
+		for i, item := range v.Value {
+			list[i] = sortSetValue(item)
+		}
+		return &types.AttributeValueMemberL{Value: list}
+	case *types.AttributeValueMemberSS:
+		sorted := append([]string(nil), v.Value...)
+		sort.Strings(sorted)
+		return &types.AttributeValueMemberSS{Value: sorted}
+	case *types.AttributeValueMemberNS:
+		sorted := append([]string(nil), v.Value...)
+		sort.Strings(sorted)
+		return &types.AttributeValueMemberNS{Value: sorted}
+	case *types.AttributeValueMemberBS:
+		sorted := append([][]byte(nil), v.Value...)
+		sort.Slice(sorted, func(i, j int) bool { return bytes.Compare(sorted[i], sorted[j]) < 0 })
+		return &types.AttributeValueMemberBS{Value: sorted}
+	default:
+		return av
+	}
+}
@@ -0,0 +1,30 @@
+package go_dynamodb_wrapper
+
+import (
+	"fmt"
+	"strings"
+)
+
+// projectionExpression builds a ProjectionExpression for document paths such as
+// "profile.address.city" or "orders[0].id", using a placeholder for every path
+// segment so reserved words and special characters never get in the way. The
+// placeholders are added to names.
+func projectionExpression(paths []string, names map[string]string) string {
+	projected := make([]string, 0, len(paths))
+	for i, path := range paths {
+		segments := strings.Split(path, ".")
+		for j, segment := range segments {
+			attr, index := segment, ""
+			if k := strings.Index(segment, "["); k >= 0 {
+				attr, index = segment[:k], segment[k:]
+			}
+
+			placeholder := fmt.Sprintf("#p%d_%d", i, j)
+			names[placeholder] = attr
+			segments[j] = placeholder + index
+		}
+		projected = append(projected, strings.Join(segments, "."))
+	}
+
+	return strings.Join(projected, ", ")
+}
@@ -0,0 +1,67 @@
+package go_dynamodb_wrapper
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestProjectionExpressionNestedPaths(t *testing.T) {
+	names := make(map[string]string)
+	got := projectionExpression([]string{"a.b[0].c", "orders[1][2]", "id"}, names)
+
+	if want := "#p0_0.#p0_1[0].#p0_2, #p1_0[1][2], #p2_0"; got != want {
+		t.Errorf("projectionExpression = %q, want %q", got, want)
+	}
+	wantNames := map[string]string{
+		"#p0_0": "a",
+		"#p0_1": "b",
+		"#p0_2": "c",
+		"#p1_0": "orders",
+		"#p2_0": "id",
+	}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("names = %v, want %v", names, wantNames)
+	}
+}
+
+func TestReadItemProjectionKeepsNesting(t *testing.T) {
+	ddb, fake := newFakeTable(t)
+
+	// What DynamoDB returns for the projection a.b[0].c.
+	fake.put(map[string]json.RawMessage{
+		"a":  json.RawMessage(`{"M":{"b":{"L":[{"M":{"c":{"S":"deep"}}}]}}}`),
+		"id": json.RawMessage(`{"S":"k"}`),
+	})
+
+	item, err := ddb.ReadItem("k", WithProjection("a.b[0].c"))
+	if err != nil {
+		t.Fatalf("ReadItem: %v", err)
+	}
+
+	var request struct {
+		ProjectionExpression     string
+		ExpressionAttributeNames map[string]string
+	}
+	if err := json.Unmarshal(fake.requests["GetItem"][0], &request); err != nil {
+		t.Fatal(err)
+	}
+	if request.ProjectionExpression != "#p0_0.#p0_1[0].#p0_2" {
+		t.Errorf("ProjectionExpression = %q", request.ProjectionExpression)
+	}
+	if want := map[string]string{"#p0_0": "a", "#p0_1": "b", "#p0_2": "c"}; !reflect.DeepEqual(request.ExpressionAttributeNames, want) {
+		t.Errorf("ExpressionAttributeNames = %v, want %v", request.ExpressionAttributeNames, want)
+	}
+
+	want := map[string]interface{}{
+		"id": "k",
+		"a": map[string]interface{}{
+			"b": []interface{}{
+				map[string]interface{}{"c": "deep"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(item, want) {
+		t.Errorf("ReadItem = %#v, want %#v", item, want)
+	}
+}
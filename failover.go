@@ -0,0 +1,51 @@
+package go_dynamodb_wrapper
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ReadItemWithRegion works like ReadItemRaw converted into a plain map, and also
+// returns the region that served the read: the table's own region, or the
+// fallback region of WithFallbackRegion when the read failed over.
+func (ddb *DDBTable) ReadItemWithRegion(ctx context.Context, partitionKeyValue string, opts ...ReadOption) (map[string]interface{}, string, error) {
+	input, err := ddb.getItemInput(partitionKeyValue, newReadOptions(opts))
+	if err != nil {
+		return nil, "", err
+	}
+
+	result, region, err := ddb.getItemFrom(ctx, input)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(result.Item) == 0 {
+		return nil, region, ErrItemNotFound
+	}
+
+	return ddb.convertItem(result.Item), region, nil
+}
+
+////////////////////////
+// Internal functions //
+////////////////////////
+
+// isRegionalFailure reports whether err suggests the region itself is in
+// trouble, rather than the request: server errors and network failures.
+func isRegionalFailure(err error) bool {
+	var internal *types.InternalServerError
+	if errors.As(err, &internal) {
+		return true
+	}
+
+	var responseErr *awshttp.ResponseError
+	if errors.As(err, &responseErr) {
+		return responseErr.HTTPStatusCode() >= 500
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,172 @@
+package go_dynamodb_wrapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// fakeDynamoDB is an in-memory stand-in for DynamoDB keyed by an "id" string
+// partition key. It supports PutItem, GetItem (ignoring projections, so tests
+// store the projected item), BatchWriteItem puts and unfiltered scans, and
+// records every request it receives.
+type fakeDynamoDB struct {
+	mu       sync.Mutex
+	items    map[string]map[string]json.RawMessage
+	order    []string
+	requests map[string][]json.RawMessage
+	failures map[string]int
+
+	// scanPageSize caps the items of a scan page, all items fit in one when 0.
+	scanPageSize int
+	// scanCapacity is the capacity every scan page reports consuming.
+	scanCapacity float64
+}
+
+// newFakeTable returns a table talking to a fakeDynamoDB.
+func newFakeTable(t *testing.T, opts ...Option) (*DDBTable, *fakeDynamoDB) {
+	t.Helper()
+
+	fake := &fakeDynamoDB{
+		items:    make(map[string]map[string]json.RawMessage),
+		requests: make(map[string][]json.RawMessage),
+		failures: make(map[string]int),
+	}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	ddb := &DDBTable{
+		name:             "test",
+		partitionKeyName: "id",
+		partitionKeyType: types.ScalarAttributeTypeS,
+		client: dynamodb.New(dynamodb.Options{
+			Region:           "us-east-1",
+			BaseEndpoint:     aws.String(server.URL),
+			Credentials:      aws.AnonymousCredentials{},
+			RetryMaxAttempts: 1,
+		}),
+	}
+	for _, opt := range opts {
+		opt(ddb)
+	}
+
+	return ddb, fake
+}
+
+// fail makes the next n requests of the given operation fail.
+func (f *fakeDynamoDB) fail(operation string, n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures[operation] = n
+}
+
+// calls returns how many requests of the given operation were received.
+func (f *fakeDynamoDB) calls(operation string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.requests[operation])
+}
+
+func (f *fakeDynamoDB) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Key               map[string]json.RawMessage
+		Item              map[string]json.RawMessage
+		ExclusiveStartKey map[string]json.RawMessage
+		RequestItems      map[string][]struct {
+			PutRequest *struct {
+				Item map[string]json.RawMessage
+			}
+		}
+	}
+	raw, err := io.ReadAll(r.Body)
+	if err == nil {
+		err = json.Unmarshal(raw, &body)
+	}
+	if err != nil {
+		writeFakeError(w, err.Error())
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	operation := strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "DynamoDB_20120810.")
+	f.requests[operation] = append(f.requests[operation], raw)
+	if f.failures[operation] > 0 {
+		f.failures[operation]--
+		writeFakeError(w, "injected failure")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	switch operation {
+	case "PutItem":
+		f.put(body.Item)
+		w.Write([]byte("{}"))
+	case "GetItem":
+		item, ok := f.items[string(body.Key["id"])]
+		if !ok {
+			w.Write([]byte("{}"))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"Item": item})
+	case "BatchWriteItem":
+		for _, requests := range body.RequestItems {
+			if len(requests) > batchWriteMaxItems {
+				writeFakeError(w, fmt.Sprintf("too many items requested for the BatchWriteItem call: %d", len(requests)))
+				return
+			}
+			for _, request := range requests {
+				f.put(request.PutRequest.Item)
+			}
+		}
+		w.Write([]byte(`{"UnprocessedItems":{}}`))
+	case "Scan":
+		order := f.order
+		if start := body.ExclusiveStartKey["id"]; start != nil {
+			for i, id := range order {
+				if id == string(start) {
+					order = order[i+1:]
+					break
+				}
+			}
+		}
+		page := map[string]interface{}{}
+		if f.scanPageSize > 0 && len(order) > f.scanPageSize {
+			order = order[:f.scanPageSize]
+			page["LastEvaluatedKey"] = map[string]json.RawMessage{"id": json.RawMessage(order[len(order)-1])}
+		}
+		items := make([]map[string]json.RawMessage, 0, len(order))
+		for _, id := range order {
+			items = append(items, f.items[id])
+		}
+		page["Items"], page["Count"], page["ScannedCount"] = items, len(items), len(items)
+		page["ConsumedCapacity"] = map[string]interface{}{"TableName": "test", "CapacityUnits": f.scanCapacity}
+		json.NewEncoder(w).Encode(page)
+	default:
+		writeFakeError(w, "unsupported operation "+operation)
+	}
+}
+
+func (f *fakeDynamoDB) put(item map[string]json.RawMessage) {
+	id := string(item["id"])
+	if _, ok := f.items[id]; !ok {
+		f.order = append(f.order, id)
+	}
+	f.items[id] = item
+}
+
+func writeFakeError(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]string{"__type": "com.amazon.coral.validate#ValidationException", "message": message})
+}
@@ -0,0 +1,39 @@
+package go_dynamodb_wrapper
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ScanModifiedSince returns the items whose attributeName, an epoch in seconds,
+// lies after since. The filter is applied server side but the whole table is
+// still scanned (and paid for), only a GSI on the attribute avoids that.
+func (ddb *DDBTable) ScanModifiedSince(ctx context.Context, attributeName string, since time.Time, opts ...ScanOption) ([]map[string]interface{}, error) {
+	filter := withScanFilter("#a > :since",
+		map[string]string{"#a": attributeName},
+		map[string]types.AttributeValue{":since": &types.AttributeValueMemberN{Value: strconv.FormatInt(since.Unix(), 10)}},
+	)
+
+	items, _, err := ddb.scanAll(ctx, newScanOptions(append(opts, filter)))
+	return items, err
+}
+
+// ScanMissingAttribute returns the items that have no attributeName at all,
+// e.g. to find the items a backfill still has to process.
+func (ddb *DDBTable) ScanMissingAttribute(ctx context.Context, attributeName string, opts ...ScanOption) ([]map[string]interface{}, error) {
+	filter := withScanFilter("attribute_not_exists(#a)", map[string]string{"#a": attributeName}, nil)
+
+	items, _, err := ddb.scanAll(ctx, newScanOptions(append(opts, filter)))
+	return items, err
+}
+
+// ScanHasAttribute returns the items that have attributeName, whatever its value.
+func (ddb *DDBTable) ScanHasAttribute(ctx context.Context, attributeName string, opts ...ScanOption) ([]map[string]interface{}, error) {
+	filter := withScanFilter("attribute_exists(#a)", map[string]string{"#a": attributeName}, nil)
+
+	items, _, err := ddb.scanAll(ctx, newScanOptions(append(opts, filter)))
+	return items, err
+}
@@ -0,0 +1,82 @@
+package go_dynamodb_wrapper
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Item is an item as stored in DynamoDB. Unlike the maps returned by ReadItem it
+// keeps the attribute types, and its getters only succeed when the attribute
+// exists with the expected type.
+type Item map[string]types.AttributeValue
+
+// ReadItemTyped reads the item with the given partition key as an Item.
+func (ddb *DDBTable) ReadItemTyped(ctx context.Context, partitionKeyValue string) (Item, error) {
+	key, err := ddb.key(partitionKeyValue)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &dynamodb.GetItemInput{
+		TableName:      aws.String(ddb.name),
+		Key:            key,
+		ConsistentRead: ddb.consistentRead(nil),
+	}
+
+	result, err := ddb.getItem(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Item) == 0 {
+		return nil, ErrItemNotFound
+	}
+
+	return Item(result.Item), nil
+}
+
+func (item Item) GetString(name string) (string, bool) {
+	if v, ok := item[name].(*types.AttributeValueMemberS); ok {
+		return v.Value, true
+	}
+	return "", false
+}
+
+// GetInt returns a number attribute as int64, it fails for numbers that have a
+// fractional part or don't fit into an int64.
+func (item Item) GetInt(name string) (int64, bool) {
+	v, ok := item[name].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, false
+	}
+
+	i, err := strconv.ParseInt(v.Value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return i, true
+}
+
+func (item Item) GetBool(name string) (bool, bool) {
+	if v, ok := item[name].(*types.AttributeValueMemberBOOL); ok {
+		return v.Value, true
+	}
+	return false, false
+}
+
+func (item Item) GetList(name string) ([]types.AttributeValue, bool) {
+	if v, ok := item[name].(*types.AttributeValueMemberL); ok {
+		return v.Value, true
+	}
+	return nil, false
+}
+
+func (item Item) GetMap(name string) (Item, bool) {
+	if v, ok := item[name].(*types.AttributeValueMemberM); ok {
+		return Item(v.Value), true
+	}
+	return nil, false
+}
@@ -0,0 +1,89 @@
+package go_dynamodb_wrapper
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Iterator lazily walks the items of a paginated result, fetching a page only
+// once the previous one has been consumed:
+//
+//	it := table.ScanIterator(ctx)
+//	for it.Next() {
+//		item := it.Item()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator struct {
+	ctx     context.Context
+	pages   *Paginator[map[string]types.AttributeValue]
+	convert func(map[string]types.AttributeValue) map[string]interface{}
+	limit   int
+
+	page    []map[string]types.AttributeValue
+	pos     int
+	yielded int
+	item    map[string]interface{}
+	err     error
+}
+
+// ScanIterator returns an Iterator over all items of the table.
+func (ddb *DDBTable) ScanIterator(ctx context.Context, opts ...ScanOption) *Iterator {
+	options := newScanOptions(opts)
+
+	return ddb.newIterator(ctx, ddb.pacedScanPages(ddb.scanInput(options), options.readRate), options.limit)
+}
+
+// Next advances to the next item, fetching a new page when needed. It returns
+// false once the items are exhausted or an error occurred.
+func (it *Iterator) Next() bool {
+	if it.err != nil || (it.limit > 0 && it.yielded >= it.limit) {
+		return false
+	}
+
+	for it.pos >= len(it.page) {
+		if !it.pages.HasMore() {
+			return false
+		}
+
+		items, err := it.pages.NextPage(it.ctx)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.page = items
+		it.pos = 0
+	}
+
+	it.item = it.convert(it.page[it.pos])
+	it.pos++
+	it.yielded++
+
+	return true
+}
+
+// Item returns the item the iterator currently points at.
+func (it *Iterator) Item() map[string]interface{} {
+	return it.item
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+////////////////////////
+// Internal functions //
+////////////////////////
+
+func (ddb *DDBTable) newIterator(ctx context.Context, fetch PageFunc, limit int) *Iterator {
+	return &Iterator{
+		ctx:     ctx,
+		pages:   NewPaginator(fetch, rawPageItem),
+		convert: ddb.convertItem,
+		limit:   limit,
+	}
+}
@@ -0,0 +1,126 @@
+package go_dynamodb_wrapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Key returns the partition key value of an item returned by the map based read
+// methods. It fails when the item has no partition key or one whose type doesn't
+// match the configured partition key type.
+func (ddb *DDBTable) Key(item map[string]interface{}) (string, error) {
+	value, ok := item[ddb.converter.name(ddb.partitionKeyName)]
+	if !ok {
+		return "", fmt.Errorf("item has no partition key %q", ddb.converter.name(ddb.partitionKeyName))
+	}
+
+	av, err := marshalValue(value)
+	if err != nil {
+		return "", fmt.Errorf("partition key %q: %w", ddb.partitionKeyName, err)
+	}
+
+	switch v := av.(type) {
+	case *types.AttributeValueMemberS:
+		// Number keys come back as strings unless WithNumberParsing is set.
+		if ddb.partitionKeyType == types.ScalarAttributeTypeS || isNumber(v.Value) {
+			return v.Value, nil
+		}
+	case *types.AttributeValueMemberN:
+		if ddb.partitionKeyType == types.ScalarAttributeTypeN {
+			return v.Value, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: partition key %q is of type %s but got %T", ErrKeyTypeMismatch, ddb.partitionKeyName, ddb.partitionKeyType, value)
+}
+
+////////////////////////
+// Internal functions //
+////////////////////////
+
+// key builds the primary key of the item with the given partition key value,
+// encoded according to the configured partition key type. It fails on a sharded
+// table, where the item lives under a shard suffixed key instead.
+func (ddb *DDBTable) key(partitionKeyValue string) (map[string]types.AttributeValue, error) {
+	if err := ddb.checkUnsharded("item access by partition key"); err != nil {
+		return nil, err
+	}
+
+	av, err := ddb.partitionKeyAttributeValue(partitionKeyValue)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]types.AttributeValue{ddb.partitionKeyName: av}, nil
+}
+
+// itemKey extracts the primary key (partition key and, when configured, sort key)
+// of an item as returned by the API.
+func (ddb *DDBTable) itemKey(item map[string]types.AttributeValue) map[string]types.AttributeValue {
+	key := map[string]types.AttributeValue{ddb.partitionKeyName: item[ddb.partitionKeyName]}
+	if ddb.sortKeyName != "" {
+		key[ddb.sortKeyName] = item[ddb.sortKeyName]
+	}
+	return key
+}
+
+func (ddb *DDBTable) partitionKeyAttributeValue(partitionKeyValue string) (types.AttributeValue, error) {
+	switch ddb.partitionKeyType {
+	case types.ScalarAttributeTypeS:
+		return &types.AttributeValueMemberS{Value: partitionKeyValue}, nil
+	case types.ScalarAttributeTypeN:
+		if !isNumber(partitionKeyValue) {
+			return nil, fmt.Errorf("%w: partition key %q is a number but got %q", ErrKeyTypeMismatch, ddb.partitionKeyName, partitionKeyValue)
+		}
+		return &types.AttributeValueMemberN{Value: partitionKeyValue}, nil
+	default:
+		return nil, fmt.Errorf("unsupported partition key type: %s", ddb.partitionKeyType)
+	}
+}
+
+// checkItemKey makes sure an item about to be written carries a partition key of
+// the configured type, so that a mismatch doesn't end up as an obscure
+// ValidationException.
+func (ddb *DDBTable) checkItemKey(item map[string]types.AttributeValue) error {
+	av, ok := item[ddb.partitionKeyName]
+	if !ok {
+		return fmt.Errorf("item has no partition key %q", ddb.partitionKeyName)
+	}
+
+	switch av.(type) {
+	case *types.AttributeValueMemberS:
+		if ddb.partitionKeyType == types.ScalarAttributeTypeS {
+			return nil
+		}
+	case *types.AttributeValueMemberN:
+		if ddb.partitionKeyType == types.ScalarAttributeTypeN {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: partition key %q must be of type %s, got %T", ErrKeyTypeMismatch, ddb.partitionKeyName, ddb.partitionKeyType, av)
+}
+
+// keyValueString returns the string form of a key attribute, as accepted by the
+// methods taking a partition key value.
+func keyValueString(av types.AttributeValue) string {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberS:
+		return v.Value
+	case *types.AttributeValueMemberN:
+		return v.Value
+	default:
+		return fmt.Sprintf("%v", av)
+	}
+}
+
+func isNumber(s string) bool {
+	if json.Valid([]byte(s)) {
+		_, ok := new(big.Float).SetString(s)
+		return ok
+	}
+	return false
+}
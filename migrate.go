@@ -0,0 +1,127 @@
+package go_dynamodb_wrapper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// CopyTo copies every item of the table into dest, which may live in another
+// region. Items are read page by page and converted with UnmarshalMap, so they
+// keep their exact types, after decoding the attributes that have a codec: dest
+// encodes them again with its own. transform, when not nil, is applied to each item
+// first, e.g. to rename the key attributes; returning nil skips the item.
+func (ddb *DDBTable) CopyTo(ctx context.Context, dest *DDBTable, transform func(map[string]interface{}) map[string]interface{}) error {
+	pages := NewPaginator(ddb.scanPages(ddb.scanInput(newScanOptions(nil))), func(item map[string]types.AttributeValue) (map[string]interface{}, error) {
+		return UnmarshalMap(ddb.decodedItem(item))
+	})
+	for pages.HasMore() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+
+		items := make([]map[string]interface{}, 0, len(page))
+		for _, item := range page {
+			if transform != nil {
+				item = transform(item)
+			}
+			if item != nil {
+				items = append(items, item)
+			}
+		}
+
+		if err := dest.BatchWriteItems(ctx, items); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MigrateFunc decides how MigrateItems changes an item: the returned attributes
+// are SET on the item unless skip is true.
+type MigrateFunc func(item map[string]interface{}) (update map[string]interface{}, skip bool, err error)
+
+// MigrateItems scans the whole table, calling fn for every item and updating the
+// item with the attributes it returns, like UpdateItem would, including the
+// WithTimestamps updated attribute. Items are handed to fn as produced by
+// UnmarshalMap, with the attributes that have a codec decoded. Progress can be followed with WithMigrateProgress.
+func (ddb *DDBTable) MigrateItems(ctx context.Context, fn MigrateFunc, opts ...MigrateOption) error {
+	options := newMigrateOptions(opts)
+
+	var scanned, updated int
+	pages := NewPaginator(ddb.scanPages(ddb.scanInput(newScanOptions(nil))), rawPageItem)
+	for pages.HasMore() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, dynamodbItem := range page {
+			item, err := UnmarshalMap(ddb.decodedItem(dynamodbItem))
+			if err != nil {
+				return err
+			}
+
+			update, skip, err := fn(item)
+			if err != nil {
+				return err
+			}
+			scanned++
+
+			if !skip && len(update) > 0 {
+				values, err := ddb.marshalMap(update)
+				if err != nil {
+					return err
+				}
+				if err := ddb.prepareUpdate(values); err != nil {
+					return err
+				}
+				if _, err := ddb.updateItem(ctx, ddb.setUpdateInput(ddb.itemKey(dynamodbItem), values)); err != nil {
+					return err
+				}
+				updated++
+			}
+		}
+
+		if options.progress != nil {
+			options.progress(scanned, updated)
+		}
+	}
+
+	return nil
+}
+
+// NormalizeNumbers rewrites the given number attributes of every item in plain
+// decimal notation wherever they are stored in another form, such as the
+// scientific notation earlier versions could write. It returns how many items
+// were rewritten.
+func (ddb *DDBTable) NormalizeNumbers(ctx context.Context, attributeNames []string) (int, error) {
+	fixed := 0
+	err := ddb.MigrateItems(ctx, func(item map[string]interface{}) (map[string]interface{}, bool, error) {
+		update := make(map[string]interface{})
+		for _, name := range attributeNames {
+			n, ok := item[name].(json.Number)
+			if !ok {
+				continue
+			}
+			canonical, err := canonicalNumber(n.String())
+			if err != nil {
+				return nil, false, fmt.Errorf("attribute %q: %w", name, err)
+			}
+			if canonical != n.String() {
+				update[name] = json.Number(canonical)
+			}
+		}
+		if len(update) == 0 {
+			return nil, true, nil
+		}
+		fixed++
+		return update, false, nil
+	})
+
+	return fixed, err
+}
@@ -0,0 +1,563 @@
+package go_dynamodb_wrapper
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	streamstypes "github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+	"github.com/aws/smithy-go/logging"
+)
+
+// DefaultVersionAttribute is the attribute used for optimistic locking unless
+// WithVersionAttribute says otherwise.
+const DefaultVersionAttribute = "version"
+
+// Option configures a DDBTable created with NewTable.
+type Option func(*DDBTable)
+
+// WithSortKey sets the name of the table's sort key, which is required by the
+// Query based methods.
+func WithSortKey(sortKeyName string) Option {
+	return func(ddb *DDBTable) {
+		ddb.sortKeyName = sortKeyName
+	}
+}
+
+// WithPartitionKeyType sets the type of the partition key, S (the default) or N.
+// Key values are still passed as strings and validated against this type.
+func WithPartitionKeyType(keyType types.ScalarAttributeType) Option {
+	return func(ddb *DDBTable) {
+		ddb.partitionKeyType = keyType
+	}
+}
+
+// WithVersionAttribute sets the attribute UpdateWithRetry uses for optimistic
+// locking, DefaultVersionAttribute when not set.
+func WithVersionAttribute(attributeName string) Option {
+	return func(ddb *DDBTable) {
+		ddb.versionAttribute = attributeName
+	}
+}
+
+// WithAutoCreateTable makes writes create the table (see CreateTable) when it
+// doesn't exist yet and then retry. It also sets the partition key type. Meant
+// for development environments only, never enable it in production.
+func WithAutoCreateTable(partitionKeyType types.ScalarAttributeType) Option {
+	return func(ddb *DDBTable) {
+		ddb.autoCreateTable = true
+		ddb.partitionKeyType = partitionKeyType
+	}
+}
+
+// WithDefaultTTL makes every written item expire after ttl, by setting
+// attributeName to the expiry in epoch seconds unless the item already has it.
+func WithDefaultTTL(attributeName string, ttl time.Duration) Option {
+	return func(ddb *DDBTable) {
+		ddb.defaultTTLAttribute = attributeName
+		ddb.defaultTTL = ttl
+	}
+}
+
+// WithNumberParsing makes the map returning methods return numbers as int64 or
+// float64 instead of strings, whenever that type represents them exactly.
+// Numbers that would lose precision, e.g. integers beyond 2^63 or decimals with
+// more digits than a float64 holds, are still returned as their string.
+func WithNumberParsing() Option {
+	return func(ddb *DDBTable) {
+		ddb.converter.parseNumbers = true
+	}
+}
+
+// WithConsistentReads sets whether reads of this table are strongly consistent
+// by default. It applies to item reads, queries and scans, the per-call options
+// WithConsistentRead and WithQueryConsistentRead still take precedence.
+func WithConsistentReads(consistent bool) Option {
+	return func(ddb *DDBTable) {
+		ddb.consistentReads = consistent
+	}
+}
+
+// WithWriteSharding spreads the writes of each partition over shards partitions
+// to avoid hot keys: item writes, including batches and transactions, store
+// items under their partition key suffixed with "#" and a random shard number,
+// and QuerySharded reads them back. It needs a string partition key and suits
+// append only data such as logs or counters, since rewriting an item may store
+// it in another shard. For the same reason the conditional writes
+// (WriteItemConditional, PutWithVersion, AcquireLock, TransactPut with a
+// condition) are rejected, and so is every method addressing an item by its
+// partition key (ReadItem, UpdateItem, DeleteItem, BatchGetItems,
+// TransactUpdate, TransactDelete, TransactConditionCheck...), as the stored
+// key carries the shard suffix.
+func WithWriteSharding(shards int) Option {
+	return func(ddb *DDBTable) {
+		ddb.writeShards = shards
+	}
+}
+
+// WithAttributeCodec transforms the given top level attribute with codec, e.g.
+// GzipCodec: writes store the encoded content of the string or binary value as
+// binary, and the map based reads return the decoded bytes.
+func WithAttributeCodec(attributeName string, codec AttributeCodec) Option {
+	return func(ddb *DDBTable) {
+		if ddb.converter.codecs == nil {
+			ddb.converter.codecs = make(map[string]AttributeCodec)
+		}
+		ddb.converter.codecs[attributeName] = codec
+	}
+}
+
+// WithValueCodec registers the codec WriteItemCodec and ReadItemCodec use to
+// serialize the given attribute. It can be combined with WithAttributeCodec,
+// e.g. to compress the serialized bytes.
+func WithValueCodec(attributeName string, codec ValueCodec) Option {
+	return func(ddb *DDBTable) {
+		if ddb.valueCodecs == nil {
+			ddb.valueCodecs = make(map[string]ValueCodec)
+		}
+		ddb.valueCodecs[attributeName] = codec
+	}
+}
+
+// WithTimestamps maintains audit attributes holding epochs in seconds: every
+// write sets updatedAttribute to the current time, and so does every update,
+// the expression based ones (UpdateItemExpr, RemoveFromSet, IncrementBounded,
+// TransactUpdate) through the #updatedAt and :updatedAt placeholders. Writes
+// also set createdAttribute unless the item already carries it, so an item read,
+// modified and written back keeps its creation time.
+func WithTimestamps(createdAttribute, updatedAttribute string) Option {
+	return func(ddb *DDBTable) {
+		ddb.createdAttribute = createdAttribute
+		ddb.updatedAttribute = updatedAttribute
+	}
+}
+
+// WithFallbackRegion makes item reads retry against the table's replica in the
+// given region, which must be part of the same global table, when the table's
+// own region fails with a server or network error. ReadItemWithRegion tells
+// which region served a read.
+func WithFallbackRegion(region string) Option {
+	return func(ddb *DDBTable) {
+		ddb.fallbackRegion = region
+	}
+}
+
+// WithAttributeNames renames top level attributes between how they are stored
+// and how callers see them, e.g. from snake_case to camelCase. names maps stored
+// names to returned ones: the map based reads return items under the new names,
+// and writes of maps turn them back into the stored ones. Everything else, such
+// as the key names given to NewTable, expressions and codecs, keeps using the
+// stored names.
+func WithAttributeNames(names map[string]string) Option {
+	return func(ddb *DDBTable) {
+		ddb.converter.names = names
+		ddb.storedNames = make(map[string]string, len(names))
+		for storedName, name := range names {
+			ddb.storedNames[name] = storedName
+		}
+	}
+}
+
+// WithCapacityTracking accumulates the capacity consumed by every operation of
+// the table handle, see DDBTable.ConsumedCapacity.
+func WithCapacityTracking() Option {
+	return func(ddb *DDBTable) {
+		ddb.capacity = &capacityCounter{}
+	}
+}
+
+// WithRedactedAttributes marks attributes whose values must never show up in
+// logs, see DDBTable.Redact.
+func WithRedactedAttributes(attributeNames ...string) Option {
+	return func(ddb *DDBTable) {
+		if ddb.redactedAttributes == nil {
+			ddb.redactedAttributes = make(map[string]bool, len(attributeNames))
+		}
+		for _, name := range attributeNames {
+			ddb.redactedAttributes[name] = true
+		}
+	}
+}
+
+// WithEncoderOptions customizes how structs are marshaled, e.g. to use `json`
+// tags instead of `dynamodbav` ones:
+//
+//	WithEncoderOptions(func(o *attributevalue.EncoderOptions) { o.TagKey = "json" })
+func WithEncoderOptions(optFns ...func(*attributevalue.EncoderOptions)) Option {
+	return func(ddb *DDBTable) {
+		ddb.encoderOptions = append(ddb.encoderOptions, optFns...)
+	}
+}
+
+// WithDecoderOptions customizes how items are unmarshaled into structs.
+func WithDecoderOptions(optFns ...func(*attributevalue.DecoderOptions)) Option {
+	return func(ddb *DDBTable) {
+		ddb.decoderOptions = append(ddb.decoderOptions, optFns...)
+	}
+}
+
+// WithEndpoint sends the requests to a custom endpoint, e.g. DynamoDB Local at
+// "http://localhost:8000".
+func WithEndpoint(endpoint string) Option {
+	return func(ddb *DDBTable) {
+		ddb.endpoint = endpoint
+	}
+}
+
+// WithHTTPClient makes the SDK send its requests through the given client,
+// e.g. an *http.Client with a proxy, custom timeouts or TLS settings.
+func WithHTTPClient(client aws.HTTPClient) Option {
+	return func(ddb *DDBTable) {
+		ddb.configOptions = append(ddb.configOptions, config.WithHTTPClient(client))
+	}
+}
+
+// WithRetryMode selects the SDK's retry strategy. aws.RetryModeAdaptive adds
+// client side rate limiting that backs off based on the observed throttling.
+func WithRetryMode(mode aws.RetryMode) Option {
+	return func(ddb *DDBTable) {
+		ddb.configOptions = append(ddb.configOptions, config.WithRetryMode(mode))
+	}
+}
+
+// WithLogger routes the SDK's own log output into logger and turns on the
+// logging of retry attempts.
+func WithLogger(logger logging.Logger) Option {
+	return func(ddb *DDBTable) {
+		ddb.configOptions = append(ddb.configOptions, config.WithLogger(logger), config.WithClientLogMode(aws.LogRetries))
+	}
+}
+
+// ReadOption configures a single ReadItem call.
+type ReadOption func(*readOptions)
+
+type readOptions struct {
+	projection     []string
+	consistentRead *bool
+	bypassCache    bool
+}
+
+// WithProjection only reads the given attributes. Nested document paths such as
+// "profile.name" or "orders[0].id" are supported and keep their structure in the
+// returned map.
+func WithProjection(paths ...string) ReadOption {
+	return func(o *readOptions) {
+		o.projection = append(o.projection, paths...)
+	}
+}
+
+// WithConsistentRead chooses between a strongly consistent (true) and an
+// eventually consistent read. A strongly consistent read costs twice as much.
+func WithConsistentRead(consistent bool) ReadOption {
+	return func(o *readOptions) {
+		o.consistentRead = aws.Bool(consistent)
+	}
+}
+
+// WithoutCache makes a CachedTable read the item from the table, without using
+// or filling its cache. It has no effect on other reads.
+func WithoutCache() ReadOption {
+	return func(o *readOptions) {
+		o.bypassCache = true
+	}
+}
+
+func newReadOptions(opts []ReadOption) *readOptions {
+	options := &readOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// ScanOption configures a single ScanTable call.
+type ScanOption func(*scanOptions)
+
+type scanOptions struct {
+	limit          int
+	consistentRead *bool
+	readRate       float64
+	indexName      string
+	resumeTokens   []string
+
+	filterExpression string
+	expressionNames  map[string]string
+	expressionValues map[string]types.AttributeValue
+}
+
+// WithScanLimit stops the scan as soon as limit items have been collected.
+func WithScanLimit(limit int) ScanOption {
+	return func(o *scanOptions) {
+		o.limit = limit
+	}
+}
+
+// WithScanConsistentRead makes the scan strongly consistent. Beware that a
+// consistent scan consumes twice the read capacity of a regular one.
+func WithScanConsistentRead() ScanOption {
+	return func(o *scanOptions) {
+		o.consistentRead = aws.Bool(true)
+	}
+}
+
+// WithScanRateLimit paces the scan so that it consumes about
+// capacityUnitsPerSecond read capacity units per second on average, leaving the
+// rest of the table's throughput to online traffic. Pages are still fetched
+// whole, so a single page may briefly exceed the rate. ScanTableParallel
+// splits the rate evenly among its segments.
+func WithScanRateLimit(capacityUnitsPerSecond float64) ScanOption {
+	return func(o *scanOptions) {
+		o.readRate = capacityUnitsPerSecond
+	}
+}
+
+// WithScanIndex scans the given secondary index instead of the table. Global
+// secondary indexes don't support consistent reads.
+func WithScanIndex(indexName string) ScanOption {
+	return func(o *scanOptions) {
+		o.indexName = indexName
+	}
+}
+
+// WithScanResumeTokens resumes a ScanTableParallel from the per-segment tokens of
+// a *ParallelScanError.
+func WithScanResumeTokens(tokens []string) ScanOption {
+	return func(o *scanOptions) {
+		o.resumeTokens = tokens
+	}
+}
+
+// withScanFilter is used by the scan helpers building their own filters.
+func withScanFilter(filterExpression string, names map[string]string, values map[string]types.AttributeValue) ScanOption {
+	return func(o *scanOptions) {
+		o.filterExpression = filterExpression
+		o.expressionNames = names
+		o.expressionValues = values
+	}
+}
+
+func newScanOptions(opts []ScanOption) *scanOptions {
+	options := &scanOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// BatchGetOption configures a single BatchGetItems call.
+type BatchGetOption func(*batchGetOptions)
+
+type batchGetOptions struct {
+	consistentRead *bool
+	projection     []string
+}
+
+// WithBatchConsistentRead makes BatchGetItems use strongly consistent reads.
+func WithBatchConsistentRead() BatchGetOption {
+	return func(o *batchGetOptions) {
+		o.consistentRead = aws.Bool(true)
+	}
+}
+
+// WithBatchProjection only reads the given attributes (or document paths) of
+// each item, see WithProjection.
+func WithBatchProjection(paths ...string) BatchGetOption {
+	return func(o *batchGetOptions) {
+		o.projection = append(o.projection, paths...)
+	}
+}
+
+func newBatchGetOptions(opts []BatchGetOption) *batchGetOptions {
+	options := &batchGetOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// QueryOption configures a single partition query.
+type QueryOption func(*queryOptions)
+
+type queryOptions struct {
+	sortKeyPrefix string
+	maxItems      int
+	startKey      map[string]types.AttributeValue
+	descending    bool
+
+	consistentRead *bool
+}
+
+// WithSortKeyPrefix restricts a query to the items whose sort key begins with prefix.
+func WithSortKeyPrefix(prefix string) QueryOption {
+	return func(o *queryOptions) {
+		o.sortKeyPrefix = prefix
+	}
+}
+
+// WithQueryMaxItems stops the query once maxItems items have been collected.
+func WithQueryMaxItems(maxItems int) QueryOption {
+	return func(o *queryOptions) {
+		o.maxItems = maxItems
+	}
+}
+
+// WithQueryDescending returns the items by descending sort key instead of
+// ascending.
+func WithQueryDescending() QueryOption {
+	return func(o *queryOptions) {
+		o.descending = true
+	}
+}
+
+// WithQueryConsistentRead chooses between a strongly consistent (true) and an
+// eventually consistent query, overriding the table default.
+func WithQueryConsistentRead(consistent bool) QueryOption {
+	return func(o *queryOptions) {
+		o.consistentRead = aws.Bool(consistent)
+	}
+}
+
+// WithQueryStartKey resumes a query from the key returned by a previous call.
+func WithQueryStartKey(startKey map[string]types.AttributeValue) QueryOption {
+	return func(o *queryOptions) {
+		o.startKey = startKey
+	}
+}
+
+func newQueryOptions(opts []QueryOption) *queryOptions {
+	options := &queryOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// MigrateOption configures a single MigrateItems call.
+type MigrateOption func(*migrateOptions)
+
+type migrateOptions struct {
+	progress func(scanned, updated int)
+}
+
+// WithMigrateProgress calls progress after every scanned page with the number of
+// items scanned and updated so far.
+func WithMigrateProgress(progress func(scanned, updated int)) MigrateOption {
+	return func(o *migrateOptions) {
+		o.progress = progress
+	}
+}
+
+func newMigrateOptions(opts []MigrateOption) *migrateOptions {
+	options := &migrateOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// StreamOption configures a single ConsumeStream call.
+type StreamOption func(*streamOptions)
+
+type streamOptions struct {
+	iteratorType streamstypes.ShardIteratorType
+	checkpoints  map[string]string
+}
+
+// WithStreamFromLatest only delivers records written after the consumer started,
+// instead of everything still retained by the stream.
+func WithStreamFromLatest() StreamOption {
+	return func(o *streamOptions) {
+		o.iteratorType = streamstypes.ShardIteratorTypeLatest
+	}
+}
+
+// WithStreamCheckpoints resumes the given shards (shard ID to sequence number)
+// right after the last record processed.
+func WithStreamCheckpoints(checkpoints map[string]string) StreamOption {
+	return func(o *streamOptions) {
+		o.checkpoints = checkpoints
+	}
+}
+
+func newStreamOptions(opts []StreamOption) *streamOptions {
+	options := &streamOptions{iteratorType: streamstypes.ShardIteratorTypeTrimHorizon}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// CreateTableOption configures a CreateTable call.
+type CreateTableOption func(*createTableOptions)
+
+type createTableOptions struct {
+	input []func(*dynamodb.CreateTableInput)
+}
+
+// WithKMSEncryption encrypts the table at rest with the given customer managed
+// KMS key (key ID, ARN or alias) instead of the AWS owned key.
+func WithKMSEncryption(kmsKeyID string) CreateTableOption {
+	return func(o *createTableOptions) {
+		o.input = append(o.input, func(input *dynamodb.CreateTableInput) {
+			input.SSESpecification = kmsSSESpecification(kmsKeyID)
+		})
+	}
+}
+
+func newCreateTableOptions(opts []CreateTableOption) *createTableOptions {
+	options := &createTableOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// ExportOption configures an ExportJSONL call.
+type ExportOption func(*exportOptions)
+
+type exportOptions struct {
+	sortMapKeys bool
+}
+
+// SortMapKeys makes the export deterministic: on top of map keys, which are
+// always sorted, the elements of string, number and binary sets are sorted too.
+// ItemHash needs no option, it always hashes items in sorted order.
+func SortMapKeys() ExportOption {
+	return func(o *exportOptions) {
+		o.sortMapKeys = true
+	}
+}
+
+func newExportOptions(opts []ExportOption) *exportOptions {
+	options := &exportOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// ContributorInsightsOption configures an Enable/DisableContributorInsights call.
+type ContributorInsightsOption func(*contributorInsightsOptions)
+
+type contributorInsightsOptions struct {
+	indexName string
+}
+
+// WithInsightsIndex applies the change to the given secondary index instead of
+// the table itself.
+func WithInsightsIndex(indexName string) ContributorInsightsOption {
+	return func(o *contributorInsightsOptions) {
+		o.indexName = indexName
+	}
+}
+
+func newContributorInsightsOptions(opts []ContributorInsightsOption) *contributorInsightsOptions {
+	options := &contributorInsightsOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
@@ -0,0 +1,165 @@
+package go_dynamodb_wrapper
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// PageFunc fetches the page of a paginated operation that starts at startKey, nil
+// for the first page. It returns the page's items and the key the next page
+// starts at, which is nil after the last page.
+type PageFunc func(ctx context.Context, startKey map[string]types.AttributeValue) (items []map[string]types.AttributeValue, lastEvaluatedKey map[string]types.AttributeValue, err error)
+
+// Paginator pages through the results of a paginated operation, converting each
+// item into a T:
+//
+//	pages := table.ScanPaginator()
+//	for pages.HasMore() {
+//		items, err := pages.NextPage(ctx)
+//		...
+//	}
+type Paginator[T any] struct {
+	fetch    PageFunc
+	convert  func(map[string]types.AttributeValue) (T, error)
+	startKey map[string]types.AttributeValue
+	done     bool
+}
+
+// NewPaginator returns a Paginator over the pages fetch returns, e.g. for an
+// operation the package doesn't wrap, converting items with convert.
+func NewPaginator[T any](fetch PageFunc, convert func(map[string]types.AttributeValue) (T, error)) *Paginator[T] {
+	return &Paginator[T]{fetch: fetch, convert: convert}
+}
+
+// ScanPaginator returns a Paginator over all items of the table. WithScanLimit
+// is not applied, it's up to the caller to stop paging.
+func (ddb *DDBTable) ScanPaginator(opts ...ScanOption) *Paginator[map[string]interface{}] {
+	options := newScanOptions(opts)
+
+	return NewPaginator(ddb.pacedScanPages(ddb.scanInput(options), options.readRate), ddb.convertPageItem)
+}
+
+// QueryPaginator returns a Paginator over the items of the given partition,
+// starting at WithQueryStartKey if set. WithQueryMaxItems is not applied.
+func (ddb *DDBTable) QueryPaginator(partitionKeyValue string, opts ...QueryOption) (*Paginator[map[string]interface{}], error) {
+	options := newQueryOptions(opts)
+
+	input, err := ddb.partitionQueryInput(partitionKeyValue, options)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := NewPaginator(ddb.queryPages(input), ddb.convertPageItem)
+	pages.startKey = options.startKey
+
+	return pages, nil
+}
+
+// HasMore reports whether there are pages left to fetch.
+func (p *Paginator[T]) HasMore() bool {
+	return !p.done
+}
+
+// NextPage fetches and converts the next page. It returns no items once all
+// pages have been fetched.
+func (p *Paginator[T]) NextPage(ctx context.Context) ([]T, error) {
+	if p.done {
+		return nil, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	items, lastEvaluatedKey, err := p.fetch(ctx, p.startKey)
+	if err != nil {
+		return nil, err
+	}
+
+	page := make([]T, 0, len(items))
+	for _, item := range items {
+		v, err := p.convert(item)
+		if err != nil {
+			return nil, err
+		}
+		page = append(page, v)
+	}
+
+	p.startKey = lastEvaluatedKey
+	p.done = lastEvaluatedKey == nil
+
+	return page, nil
+}
+
+// LastEvaluatedKey returns the key the next page starts at, e.g. to resume
+// later with WithQueryStartKey. It is nil once all pages have been fetched.
+func (p *Paginator[T]) LastEvaluatedKey() map[string]types.AttributeValue {
+	return p.startKey
+}
+
+////////////////////////
+// Internal functions //
+////////////////////////
+
+func (ddb *DDBTable) scanPages(input *dynamodb.ScanInput) PageFunc {
+	return ddb.scanPagesWith(input, nil)
+}
+
+// pacedScanPages is scanPages holding every page back until the capacity it
+// consumed fits within readRate units per second, see WithScanRateLimit.
+func (ddb *DDBTable) pacedScanPages(input *dynamodb.ScanInput, readRate float64) PageFunc {
+	limiter := newCapacityLimiter(readRate)
+	return func(ctx context.Context, startKey map[string]types.AttributeValue) ([]map[string]types.AttributeValue, map[string]types.AttributeValue, error) {
+		return ddb.scanPagesWith(input, func(result *dynamodb.ScanOutput) error {
+			return limiter.wait(ctx, result.ConsumedCapacity)
+		})(ctx, startKey)
+	}
+}
+
+// scanPagesWith is scanPages handing every response to observe, when not nil,
+// for the callers needing more than the items, such as the counts.
+func (ddb *DDBTable) scanPagesWith(input *dynamodb.ScanInput, observe func(*dynamodb.ScanOutput) error) PageFunc {
+	return func(ctx context.Context, startKey map[string]types.AttributeValue) ([]map[string]types.AttributeValue, map[string]types.AttributeValue, error) {
+		input.ExclusiveStartKey = startKey
+		result, err := ddb.scan(ctx, input)
+		if err != nil {
+			return nil, nil, err
+		}
+		if observe != nil {
+			if err := observe(result); err != nil {
+				return nil, nil, err
+			}
+		}
+		return result.Items, result.LastEvaluatedKey, nil
+	}
+}
+
+func (ddb *DDBTable) queryPages(input *dynamodb.QueryInput) PageFunc {
+	return ddb.queryPagesWith(input, nil)
+}
+
+// queryPagesWith is the query counterpart of scanPagesWith.
+func (ddb *DDBTable) queryPagesWith(input *dynamodb.QueryInput, observe func(*dynamodb.QueryOutput) error) PageFunc {
+	return func(ctx context.Context, startKey map[string]types.AttributeValue) ([]map[string]types.AttributeValue, map[string]types.AttributeValue, error) {
+		input.ExclusiveStartKey = startKey
+		result, err := ddb.query(ctx, input)
+		if err != nil {
+			return nil, nil, err
+		}
+		if observe != nil {
+			if err := observe(result); err != nil {
+				return nil, nil, err
+			}
+		}
+		return result.Items, result.LastEvaluatedKey, nil
+	}
+}
+
+func (ddb *DDBTable) convertPageItem(item map[string]types.AttributeValue) (map[string]interface{}, error) {
+	return ddb.convertItem(item), nil
+}
+
+func rawPageItem(item map[string]types.AttributeValue) (map[string]types.AttributeValue, error) {
+	return item, nil
+}
@@ -0,0 +1,128 @@
+package go_dynamodb_wrapper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// ScanSegmentDone is the resume token of a segment scanned completely.
+const ScanSegmentDone = "done"
+
+// ParallelScanError is returned when a parallel scan fails. Tokens holds a
+// resume token per segment, to be passed to WithScanResumeTokens so that the
+// scan continues where it stopped instead of starting over.
+type ParallelScanError struct {
+	Tokens []string
+	Err    error
+}
+
+func (e *ParallelScanError) Error() string {
+	return fmt.Sprintf("parallel scan failed: %v", e.Err)
+}
+
+func (e *ParallelScanError) Unwrap() error {
+	return e.Err
+}
+
+// ScanTableParallel scans the table split into the given number of segments, all
+// read concurrently. When a segment fails the others are stopped and the items
+// read so far are returned along with a *ParallelScanError, whose tokens resume
+// the scan right after those items. WithScanLimit is not applied.
+func (ddb *DDBTable) ScanTableParallel(ctx context.Context, segments int, opts ...ScanOption) ([]map[string]interface{}, error) {
+	if segments < 1 {
+		return nil, fmt.Errorf("segments must be at least 1, got %d", segments)
+	}
+	options := newScanOptions(opts)
+
+	tokens := options.resumeTokens
+	if tokens == nil {
+		tokens = make([]string, segments)
+	}
+	if len(tokens) != segments {
+		return nil, fmt.Errorf("got %d resume tokens for %d segments", len(tokens), segments)
+	}
+	tokens = append([]string(nil), tokens...)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	results := make([][]map[string]interface{}, segments)
+	errs := make([]error, segments)
+
+	for segment := 0; segment < segments; segment++ {
+		if tokens[segment] == ScanSegmentDone {
+			continue
+		}
+
+		wg.Add(1)
+		go func(segment int) {
+			defer wg.Done()
+			if err := ddb.scanSegment(ctx, options, segment, segments, &results[segment], &tokens[segment]); err != nil {
+				errs[segment] = err
+				cancel()
+			}
+		}(segment)
+	}
+	wg.Wait()
+
+	var returnedList []map[string]interface{}
+	for _, items := range results {
+		returnedList = append(returnedList, items...)
+	}
+
+	var firstErr error
+	for _, err := range errs {
+		if err != nil && (firstErr == nil || errors.Is(firstErr, context.Canceled)) {
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		return returnedList, &ParallelScanError{Tokens: tokens, Err: firstErr}
+	}
+
+	return returnedList, nil
+}
+
+////////////////////////
+// Internal functions //
+////////////////////////
+
+// scanSegment reads one segment, appending to items and keeping token pointing
+// right after the last page appended.
+func (ddb *DDBTable) scanSegment(ctx context.Context, options *scanOptions, segment, segments int, items *[]map[string]interface{}, token *string) error {
+	input := ddb.scanInput(options)
+	input.Segment = aws.Int32(int32(segment))
+	input.TotalSegments = aws.Int32(int32(segments))
+
+	startKey, err := DecodeCursor(*token)
+	if err != nil {
+		return fmt.Errorf("invalid resume token of segment %d: %w", segment, err)
+	}
+
+	// The segments share the rate limit evenly.
+	pages := NewPaginator(ddb.pacedScanPages(input, options.readRate/float64(segments)), ddb.convertPageItem)
+	pages.startKey = startKey
+	for pages.HasMore() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+
+		next := ScanSegmentDone
+		if pages.HasMore() {
+			if next, err = EncodeCursor(pages.LastEvaluatedKey()); err != nil {
+				return err
+			}
+		}
+
+		*items = append(*items, page...)
+		*token = next
+	}
+
+	return nil
+}
@@ -0,0 +1,276 @@
+package go_dynamodb_wrapper
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Query returns the items of the given partition. When WithQueryMaxItems caps
+// the result, the returned key is where the next call should resume from using
+// WithQueryStartKey; it is nil once the partition has been read completely.
+func (ddb *DDBTable) Query(ctx context.Context, partitionKeyValue string, opts ...QueryOption) ([]map[string]interface{}, map[string]types.AttributeValue, error) {
+	items, lastKey, err := ddb.queryPartition(ctx, partitionKeyValue, newQueryOptions(opts))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	returnedList := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		returnedList = append(returnedList, ddb.convertItem(item))
+	}
+
+	return returnedList, lastKey, nil
+}
+
+// QueryInto queries the given partition of table like Query and unmarshals each
+// item into a T with the table's attributevalue decoder, appending them to out.
+// It is a function rather than a method since methods can't be generic.
+func QueryInto[T any](ctx context.Context, table *DDBTable, partitionKeyValue string, out *[]T, opts ...QueryOption) error {
+	items, _, err := table.queryPartition(ctx, partitionKeyValue, newQueryOptions(opts))
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		var v T
+		if err := attributevalue.UnmarshalMapWithOptions(item, &v, table.decoderOptions...); err != nil {
+			return fmt.Errorf("failed to unmarshal item: %w", err)
+		}
+		*out = append(*out, v)
+	}
+
+	return nil
+}
+
+// QueryByPrefix returns all items of the given partition whose sort key begins with sortKeyPrefix.
+func (ddb *DDBTable) QueryByPrefix(ctx context.Context, partitionKeyValue, sortKeyPrefix string) ([]map[string]interface{}, error) {
+	input, err := ddb.partitionQueryInput(partitionKeyValue, newQueryOptions([]QueryOption{WithSortKeyPrefix(sortKeyPrefix)}))
+	if err != nil {
+		return nil, err
+	}
+
+	return ddb.queryAll(ctx, input)
+}
+
+// QueryIterator returns an Iterator over the items of the given partition.
+func (ddb *DDBTable) QueryIterator(ctx context.Context, partitionKeyValue string, opts ...QueryOption) *Iterator {
+	options := newQueryOptions(opts)
+
+	input, err := ddb.partitionQueryInput(partitionKeyValue, options)
+	if err != nil {
+		return &Iterator{err: err}
+	}
+
+	it := ddb.newIterator(ctx, ddb.queryPages(input), options.maxItems)
+	it.pages.startKey = options.startKey
+
+	return it
+}
+
+// ReadFirst returns the first item of the given partition, i.e. the one with the
+// lowest sort key. Use the Query methods to read all of them.
+func (ddb *DDBTable) ReadFirst(ctx context.Context, partitionKeyValue string) (map[string]interface{}, error) {
+	items, _, err := ddb.Query(ctx, partitionKeyValue, WithQueryMaxItems(1))
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, ErrItemNotFound
+	}
+
+	return items[0], nil
+}
+
+// QueryBetween returns the items of the given partition whose sort key lies
+// between sortLow and sortHigh, both included, in ascending order. The bounds are
+// converted like the values of WriteItem, e.g. epochs as numbers or ISO 8601
+// timestamps as strings, and must match the sort key's type.
+func (ddb *DDBTable) QueryBetween(ctx context.Context, partitionKeyValue string, sortLow, sortHigh interface{}) ([]map[string]interface{}, error) {
+	if ddb.sortKeyName == "" {
+		return nil, ErrSortKeyNotConfigured
+	}
+
+	low, err := marshalValue(sortLow)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lower bound: %w", err)
+	}
+	high, err := marshalValue(sortHigh)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upper bound: %w", err)
+	}
+
+	input, err := ddb.partitionQueryInput(partitionKeyValue, newQueryOptions(nil))
+	if err != nil {
+		return nil, err
+	}
+	input.KeyConditionExpression = aws.String(aws.ToString(input.KeyConditionExpression) + " AND #sk BETWEEN :lo AND :hi")
+	input.ExpressionAttributeNames["#sk"] = ddb.sortKeyName
+	input.ExpressionAttributeValues[":lo"] = low
+	input.ExpressionAttributeValues[":hi"] = high
+
+	return ddb.queryAll(ctx, input)
+}
+
+// QueryLatest returns the n items of the given partition with the highest sort
+// keys, highest first. With a timestamp as sort key these are the most recent.
+// n must be at least 1.
+func (ddb *DDBTable) QueryLatest(ctx context.Context, partitionKeyValue string, n int) ([]map[string]interface{}, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("n must be at least 1, got %d", n)
+	}
+	items, _, err := ddb.Query(ctx, partitionKeyValue, WithQueryDescending(), WithQueryMaxItems(n))
+	return items, err
+}
+
+// QueryRaw runs a query built by the caller, for the features QueryOption
+// doesn't cover. The table name is filled in and all pages are read, everything
+// else is sent as is; input itself is left unmodified.
+func (ddb *DDBTable) QueryRaw(ctx context.Context, input *dynamodb.QueryInput) ([]map[string]interface{}, error) {
+	in := *input
+	in.TableName = aws.String(ddb.name)
+
+	return ddb.queryAll(ctx, &in)
+}
+
+// CountByPartition counts the items of the given partition without fetching them.
+func (ddb *DDBTable) CountByPartition(ctx context.Context, partitionKeyValue string) (int64, error) {
+	input, err := ddb.partitionQueryInput(partitionKeyValue, newQueryOptions(nil))
+	if err != nil {
+		return 0, err
+	}
+	input.Select = types.SelectCount
+
+	var count int64
+	pages := NewPaginator(ddb.queryPagesWith(input, func(result *dynamodb.QueryOutput) error {
+		count += int64(result.Count)
+		return nil
+	}), rawPageItem)
+	for pages.HasMore() {
+		if _, err := pages.NextPage(ctx); err != nil {
+			return 0, err
+		}
+	}
+
+	return count, nil
+}
+
+// GetByIndex looks up the single item whose keyName equals keyValue on the given
+// secondary index. It returns ErrItemNotFound when nothing matches and
+// ErrMultipleItemsFound when the key turns out not to be unique.
+func (ddb *DDBTable) GetByIndex(ctx context.Context, indexName, keyName, keyValue string) (map[string]interface{}, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(ddb.name),
+		IndexName:              aws.String(indexName),
+		KeyConditionExpression: aws.String("#k = :v"),
+		ExpressionAttributeNames: map[string]string{
+			"#k": keyName,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":v": &types.AttributeValueMemberS{Value: keyValue},
+		},
+		Limit: aws.Int32(2),
+	}
+
+	result, err := ddb.query(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(result.Items) {
+	case 0:
+		return nil, ErrItemNotFound
+	case 1:
+		return ddb.convertItem(result.Items[0]), nil
+	default:
+		return nil, ErrMultipleItemsFound
+	}
+}
+
+////////////////////////
+// Internal functions //
+////////////////////////
+
+func (ddb *DDBTable) partitionQueryInput(partitionKeyValue string, options *queryOptions) (*dynamodb.QueryInput, error) {
+	pk, err := ddb.partitionKeyAttributeValue(partitionKeyValue)
+	if err != nil {
+		return nil, err
+	}
+
+	keyCondition := "#pk = :pk"
+	names := map[string]string{"#pk": ddb.partitionKeyName}
+	values := map[string]types.AttributeValue{":pk": pk}
+
+	if options.sortKeyPrefix != "" {
+		if ddb.sortKeyName == "" {
+			return nil, ErrSortKeyNotConfigured
+		}
+		keyCondition += " AND begins_with(#sk, :prefix)"
+		names["#sk"] = ddb.sortKeyName
+		values[":prefix"] = &types.AttributeValueMemberS{Value: options.sortKeyPrefix}
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(ddb.name),
+		KeyConditionExpression:    aws.String(keyCondition),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+		ConsistentRead:            ddb.consistentRead(options.consistentRead),
+	}
+	if options.descending {
+		input.ScanIndexForward = aws.Bool(false)
+	}
+
+	return input, nil
+}
+
+// queryPartition reads the items of a partition as returned by the API, applying
+// the start key and item cap of the options.
+func (ddb *DDBTable) queryPartition(ctx context.Context, partitionKeyValue string, options *queryOptions) ([]map[string]types.AttributeValue, map[string]types.AttributeValue, error) {
+	input, err := ddb.partitionQueryInput(partitionKeyValue, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var items []map[string]types.AttributeValue
+
+	pages := NewPaginator(ddb.queryPages(input), rawPageItem)
+	pages.startKey = options.startKey
+	for pages.HasMore() {
+		if options.maxItems > 0 {
+			input.Limit = aws.Int32(int32(options.maxItems - len(items)))
+		}
+
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		items = append(items, page...)
+
+		if options.maxItems > 0 && len(items) >= options.maxItems {
+			return items, pages.LastEvaluatedKey(), nil
+		}
+	}
+
+	return items, nil, nil
+}
+
+func (ddb *DDBTable) queryAll(ctx context.Context, input *dynamodb.QueryInput) ([]map[string]interface{}, error) {
+	var returnedList []map[string]interface{}
+
+	pages := NewPaginator(ddb.queryPages(input), ddb.convertPageItem)
+	pages.startKey = input.ExclusiveStartKey
+	for pages.HasMore() {
+		items, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		returnedList = append(returnedList, items...)
+	}
+
+	return returnedList, nil
+}
@@ -0,0 +1,46 @@
+package go_dynamodb_wrapper
+
+// RedactedValue replaces the value of every redacted attribute in Redact's output.
+const RedactedValue = "[REDACTED]"
+
+// Redact returns a copy of item that is safe to log: the value of every attribute
+// configured with WithRedactedAttributes is replaced by RedactedValue, at any
+// nesting depth. The item itself is left untouched.
+func (ddb *DDBTable) Redact(item map[string]interface{}) map[string]interface{} {
+	if item == nil {
+		return nil
+	}
+
+	return redactMap(item, ddb.redactedAttributes)
+}
+
+////////////////////////
+// Internal functions //
+////////////////////////
+
+func redactMap(item map[string]interface{}, redacted map[string]bool) map[string]interface{} {
+	result := make(map[string]interface{}, len(item))
+	for k, v := range item {
+		if redacted[k] {
+			result[k] = RedactedValue
+			continue
+		}
+		result[k] = redactValue(v, redacted)
+	}
+	return result
+}
+
+func redactValue(value interface{}, redacted map[string]bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return redactMap(v, redacted)
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = redactValue(item, redacted)
+		}
+		return result
+	default:
+		return v
+	}
+}
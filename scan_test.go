@@ -0,0 +1,93 @@
+package go_dynamodb_wrapper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestScanRateLimit(t *testing.T) {
+	ctx := context.Background()
+	scans := map[string]func(ddb *DDBTable, opts ...ScanOption) (int, error){
+		"ScanTable": func(ddb *DDBTable, opts ...ScanOption) (int, error) {
+			items, err := ddb.ScanTable(opts...)
+			return len(items), err
+		},
+		"ScanIterator": func(ddb *DDBTable, opts ...ScanOption) (int, error) {
+			n := 0
+			it := ddb.ScanIterator(ctx, opts...)
+			for it.Next() {
+				n++
+			}
+			return n, it.Err()
+		},
+		"ScanPaginator": func(ddb *DDBTable, opts ...ScanOption) (int, error) {
+			n := 0
+			pages := ddb.ScanPaginator(opts...)
+			for pages.HasMore() {
+				items, err := pages.NextPage(ctx)
+				if err != nil {
+					return n, err
+				}
+				n += len(items)
+			}
+			return n, nil
+		},
+		"ScanTableParallel": func(ddb *DDBTable, opts ...ScanOption) (int, error) {
+			items, err := ddb.ScanTableParallel(ctx, 1, opts...)
+			return len(items), err
+		},
+		"ReadPartitionKeysList": func(ddb *DDBTable, opts ...ScanOption) (int, error) {
+			keys, err := ddb.ReadPartitionKeysList(opts...)
+			return len(keys), err
+		},
+	}
+
+	for name, scan := range scans {
+		ddb, fake := newFakeTable(t)
+		for i := 0; i < 3; i++ {
+			fake.put(map[string]json.RawMessage{"id": json.RawMessage(fmt.Sprintf(`{"S":"%d"}`, i))})
+		}
+		// Three pages of 35 units against a bucket of 100 units refilling at
+		// 100 units per second end up 5 units in debt, 50ms worth.
+		fake.scanPageSize, fake.scanCapacity = 1, 35
+
+		start := time.Now()
+		n, err := scan(ddb, WithScanRateLimit(100))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if n != 3 {
+			t.Errorf("%s returned %d items, want 3", name, n)
+		}
+		if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+			t.Errorf("%s took %v, the rate limit was not applied", name, elapsed)
+		}
+	}
+}
+
+func TestReadPartitionKeysListOptions(t *testing.T) {
+	ddb, fake := newFakeTable(t)
+	for i := 0; i < 5; i++ {
+		fake.put(map[string]json.RawMessage{"id": json.RawMessage(fmt.Sprintf(`{"S":"%d"}`, i))})
+	}
+	fake.scanPageSize = 2
+
+	keys, err := ddb.ReadPartitionKeysList(WithScanLimit(3))
+	if err != nil {
+		t.Fatalf("ReadPartitionKeysList: %v", err)
+	}
+	if want := []string{"0", "1", "2"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("ReadPartitionKeysList(WithScanLimit(3)) = %v, want %v", keys, want)
+	}
+	if got := fake.calls("Scan"); got != 2 {
+		t.Errorf("Scan requests = %d, want 2", got)
+	}
+
+	if _, err := ddb.ReadPartitionKeysList(WithScanResumeTokens([]string{""})); err == nil {
+		t.Errorf("ReadPartitionKeysList accepted WithScanResumeTokens")
+	}
+}
@@ -0,0 +1,75 @@
+package go_dynamodb_wrapper
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ValidateSchema checks item against schema, which maps the required attribute
+// names to their DynamoDB type: "S", "N", "B", "BOOL", "NULL", "M", "L", "SS",
+// "NS" or "BS". The type of each value is the one WriteItem would store it as.
+// All missing and mismatching attributes are reported, each wrapping
+// ErrSchemaMismatch. Attributes not in schema are ignored.
+func ValidateSchema(item map[string]interface{}, schema map[string]string) error {
+	names := make([]string, 0, len(schema))
+	for name := range schema {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		expected := schema[name]
+
+		value, ok := item[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%w: attribute %q is missing", ErrSchemaMismatch, name))
+			continue
+		}
+
+		av, err := marshalValue(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w: attribute %q: %v", ErrSchemaMismatch, name, err))
+			continue
+		}
+		if actual := attributeType(av); actual != expected {
+			errs = append(errs, fmt.Errorf("%w: attribute %q is %s, expected %s", ErrSchemaMismatch, name, actual, expected))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+////////////////////////
+// Internal functions //
+////////////////////////
+
+func attributeType(av types.AttributeValue) string {
+	switch av.(type) {
+	case *types.AttributeValueMemberS:
+		return "S"
+	case *types.AttributeValueMemberN:
+		return "N"
+	case *types.AttributeValueMemberB:
+		return "B"
+	case *types.AttributeValueMemberBOOL:
+		return "BOOL"
+	case *types.AttributeValueMemberNULL:
+		return "NULL"
+	case *types.AttributeValueMemberM:
+		return "M"
+	case *types.AttributeValueMemberL:
+		return "L"
+	case *types.AttributeValueMemberSS:
+		return "SS"
+	case *types.AttributeValueMemberNS:
+		return "NS"
+	case *types.AttributeValueMemberBS:
+		return "BS"
+	default:
+		return fmt.Sprintf("%T", av)
+	}
+}
@@ -0,0 +1,244 @@
+package go_dynamodb_wrapper
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Thin wrappers around the SDK client, every table operation goes through them
+// so that behaviour shared by all operations lives in one place.
+
+func (ddb *DDBTable) scan(ctx context.Context, input *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+	input.ReturnConsumedCapacity = ddb.returnConsumedCapacity(input.ReturnConsumedCapacity)
+
+	result, err := ddb.client.Scan(ctx, input)
+	if err != nil {
+		return nil, ddb.wrapError("Scan", err)
+	}
+	ddb.recordReadCapacity(result.ConsumedCapacity)
+
+	return result, nil
+}
+
+func (ddb *DDBTable) query(ctx context.Context, input *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+	input.ReturnConsumedCapacity = ddb.returnConsumedCapacity(input.ReturnConsumedCapacity)
+
+	result, err := ddb.client.Query(ctx, input)
+	if err != nil {
+		return nil, ddb.wrapError("Query", err)
+	}
+	ddb.recordReadCapacity(result.ConsumedCapacity)
+
+	return result, nil
+}
+
+func (ddb *DDBTable) getItem(ctx context.Context, input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+	result, _, err := ddb.getItemFrom(ctx, input)
+	return result, err
+}
+
+// getItemFrom also returns the region that served the read, which is the
+// fallback region when the table's own failed with a regional error.
+func (ddb *DDBTable) getItemFrom(ctx context.Context, input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, string, error) {
+	input.ReturnConsumedCapacity = ddb.returnConsumedCapacity(input.ReturnConsumedCapacity)
+
+	region := ddb.region
+	result, err := ddb.client.GetItem(ctx, input)
+	if err != nil && ddb.fallbackClient != nil && ctx.Err() == nil && isRegionalFailure(err) {
+		region = ddb.fallbackRegion
+		result, err = ddb.fallbackClient.GetItem(ctx, input)
+	}
+	if err != nil {
+		return nil, "", ddb.wrapError("GetItem", err)
+	}
+	ddb.recordReadCapacity(result.ConsumedCapacity)
+
+	return result, region, nil
+}
+
+func (ddb *DDBTable) batchGetItem(ctx context.Context, input *dynamodb.BatchGetItemInput) (*dynamodb.BatchGetItemOutput, error) {
+	input.ReturnConsumedCapacity = ddb.returnConsumedCapacity(input.ReturnConsumedCapacity)
+
+	result, err := ddb.client.BatchGetItem(ctx, input)
+	if err != nil {
+		return nil, ddb.wrapError("BatchGetItem", err)
+	}
+	ddb.recordReadCapacity(capacityPointers(result.ConsumedCapacity)...)
+
+	return result, nil
+}
+
+func (ddb *DDBTable) batchWriteItem(ctx context.Context, input *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error) {
+	if ddb.written != nil {
+		var items []map[string]types.AttributeValue
+		for _, request := range input.RequestItems[ddb.name] {
+			if request.PutRequest != nil {
+				items = append(items, request.PutRequest.Item)
+			}
+			if request.DeleteRequest != nil {
+				items = append(items, request.DeleteRequest.Key)
+			}
+		}
+		defer ddb.written(items...)
+	}
+	input.ReturnConsumedCapacity = ddb.returnConsumedCapacity(input.ReturnConsumedCapacity)
+
+	result, err := ddb.client.BatchWriteItem(ctx, input)
+	if err != nil {
+		retry, err := ddb.createTableIfEnabled(ctx, ddb.wrapError("BatchWriteItem", err))
+		if !retry {
+			return nil, err
+		}
+		if result, err = ddb.client.BatchWriteItem(ctx, input); err != nil {
+			return nil, ddb.wrapError("BatchWriteItem", err)
+		}
+	}
+	ddb.recordWriteCapacity(capacityPointers(result.ConsumedCapacity)...)
+
+	return result, nil
+}
+
+func (ddb *DDBTable) putItem(ctx context.Context, input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+	if ddb.written != nil {
+		defer ddb.written(input.Item)
+	}
+	input.ReturnConsumedCapacity = ddb.returnConsumedCapacity(input.ReturnConsumedCapacity)
+
+	result, err := ddb.client.PutItem(ctx, input)
+	if err != nil {
+		retry, err := ddb.createTableIfEnabled(ctx, ddb.wrapError("PutItem", err))
+		if !retry {
+			return nil, err
+		}
+		if result, err = ddb.client.PutItem(ctx, input); err != nil {
+			return nil, ddb.wrapError("PutItem", err)
+		}
+	}
+	ddb.recordWriteCapacity(result.ConsumedCapacity)
+
+	return result, nil
+}
+
+func (ddb *DDBTable) updateItem(ctx context.Context, input *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+	if ddb.written != nil {
+		defer ddb.written(input.Key)
+	}
+	input.ReturnConsumedCapacity = ddb.returnConsumedCapacity(input.ReturnConsumedCapacity)
+
+	result, err := ddb.client.UpdateItem(ctx, input)
+	if err != nil {
+		return nil, ddb.wrapError("UpdateItem", err)
+	}
+	ddb.recordWriteCapacity(result.ConsumedCapacity)
+
+	return result, nil
+}
+
+func (ddb *DDBTable) deleteItem(ctx context.Context, input *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+	if ddb.written != nil {
+		defer ddb.written(input.Key)
+	}
+	input.ReturnConsumedCapacity = ddb.returnConsumedCapacity(input.ReturnConsumedCapacity)
+
+	result, err := ddb.client.DeleteItem(ctx, input)
+	if err != nil {
+		return nil, ddb.wrapError("DeleteItem", err)
+	}
+	ddb.recordWriteCapacity(result.ConsumedCapacity)
+
+	return result, nil
+}
+
+func (ddb *DDBTable) transactWriteItems(ctx context.Context, input *dynamodb.TransactWriteItemsInput) (*dynamodb.TransactWriteItemsOutput, error) {
+	if ddb.written != nil {
+		var items []map[string]types.AttributeValue
+		for _, item := range input.TransactItems {
+			switch {
+			case item.Put != nil:
+				items = append(items, item.Put.Item)
+			case item.Update != nil:
+				items = append(items, item.Update.Key)
+			case item.Delete != nil:
+				items = append(items, item.Delete.Key)
+			}
+		}
+		defer ddb.written(items...)
+	}
+	input.ReturnConsumedCapacity = ddb.returnConsumedCapacity(input.ReturnConsumedCapacity)
+
+	result, err := ddb.client.TransactWriteItems(ctx, input)
+	if err != nil {
+		return nil, ddb.wrapError("TransactWriteItems", err)
+	}
+	ddb.recordWriteCapacity(capacityPointers(result.ConsumedCapacity)...)
+
+	return result, nil
+}
+
+func (ddb *DDBTable) describeTable(ctx context.Context) (*types.TableDescription, error) {
+	result, err := ddb.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(ddb.name)})
+	if err != nil {
+		return nil, ddb.wrapError("DescribeTable", err)
+	}
+
+	return result.Table, nil
+}
+
+func (ddb *DDBTable) updateTable(ctx context.Context, input *dynamodb.UpdateTableInput) (*types.TableDescription, error) {
+	result, err := ddb.client.UpdateTable(ctx, input)
+	if err != nil {
+		return nil, ddb.wrapError("UpdateTable", err)
+	}
+
+	return result.TableDescription, nil
+}
+
+func (ddb *DDBTable) exportTableToPointInTime(ctx context.Context, input *dynamodb.ExportTableToPointInTimeInput) (*dynamodb.ExportTableToPointInTimeOutput, error) {
+	result, err := ddb.client.ExportTableToPointInTime(ctx, input)
+	if err != nil {
+		return nil, ddb.wrapError("ExportTableToPointInTime", err)
+	}
+
+	return result, nil
+}
+
+func (ddb *DDBTable) importTable(ctx context.Context, input *dynamodb.ImportTableInput) (*dynamodb.ImportTableOutput, error) {
+	result, err := ddb.client.ImportTable(ctx, input)
+	if err != nil {
+		return nil, ddb.wrapError("ImportTable", err)
+	}
+
+	return result, nil
+}
+
+func (ddb *DDBTable) updateContributorInsights(ctx context.Context, input *dynamodb.UpdateContributorInsightsInput) (*dynamodb.UpdateContributorInsightsOutput, error) {
+	result, err := ddb.client.UpdateContributorInsights(ctx, input)
+	if err != nil {
+		return nil, ddb.wrapError("UpdateContributorInsights", err)
+	}
+
+	return result, nil
+}
+
+// wrapError turns SDK errors into a *DynamoError naming the failed operation,
+// translating the errors common to all operations into the package's own types.
+func (ddb *DDBTable) wrapError(op string, err error) error {
+	var rnf *types.ResourceNotFoundException
+	if errors.As(err, &rnf) {
+		err = &TableNotFoundError{Table: ddb.name, Err: err}
+	}
+
+	return &DynamoError{Op: op, Table: ddb.name, Err: err}
+}
+
+func capacityPointers(consumed []types.ConsumedCapacity) []*types.ConsumedCapacity {
+	pointers := make([]*types.ConsumedCapacity, len(consumed))
+	for i := range consumed {
+		pointers[i] = &consumed[i]
+	}
+	return pointers
+}
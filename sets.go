@@ -0,0 +1,90 @@
+package go_dynamodb_wrapper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// StringSet, NumberSet and BinarySet are how ReadItem, ScanTable and the other
+// map returning methods represent DynamoDB sets, which tells them apart from
+// lists. Passing them to WriteItem stores them as sets again.
+type (
+	StringSet []string
+	NumberSet []string
+	BinarySet [][]byte
+)
+
+// RemoveFromSet atomically removes values from the set stored in attributeName.
+// The values must all be strings, all numbers or all []byte, matching the type
+// of the stored set.
+func (ddb *DDBTable) RemoveFromSet(ctx context.Context, partitionKeyValue, attributeName string, values []interface{}) error {
+	key, err := ddb.key(partitionKeyValue)
+	if err != nil {
+		return err
+	}
+	set, err := marshalSet(values)
+	if err != nil {
+		return err
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(ddb.name),
+		Key:                       key,
+		UpdateExpression:          aws.String("DELETE #attr :vals"),
+		ExpressionAttributeNames:  map[string]string{"#attr": attributeName},
+		ExpressionAttributeValues: map[string]types.AttributeValue{":vals": set},
+	}
+	input.UpdateExpression, input.ExpressionAttributeNames, input.ExpressionAttributeValues = ddb.stampUpdateExpression(*input.UpdateExpression, input.ExpressionAttributeNames, input.ExpressionAttributeValues)
+
+	_, err = ddb.updateItem(ctx, input)
+	return err
+}
+
+////////////////////////
+// Internal functions //
+////////////////////////
+
+// marshalSet builds a string, number or binary set out of values, which must all
+// be of the same kind.
+func marshalSet(values []interface{}) (types.AttributeValue, error) {
+	if len(values) == 0 {
+		return nil, errors.New("a set must contain at least one value")
+	}
+
+	var ss, ns []string
+	var bs [][]byte
+	for _, value := range values {
+		av, err := marshalValue(value)
+		if err != nil {
+			return nil, err
+		}
+
+		switch v := av.(type) {
+		case *types.AttributeValueMemberS:
+			ss = append(ss, v.Value)
+		case *types.AttributeValueMemberN:
+			ns = append(ns, v.Value)
+		case *types.AttributeValueMemberB:
+			bs = append(bs, v.Value)
+		default:
+			return nil, fmt.Errorf("unsupported set element type: %v", reflect.TypeOf(value))
+		}
+	}
+
+	switch len(values) {
+	case len(ss):
+		return &types.AttributeValueMemberSS{Value: ss}, nil
+	case len(ns):
+		return &types.AttributeValueMemberNS{Value: ns}, nil
+	case len(bs):
+		return &types.AttributeValueMemberBS{Value: bs}, nil
+	default:
+		return nil, errors.New("set elements must all be strings, numbers or binary")
+	}
+}
@@ -0,0 +1,88 @@
+package go_dynamodb_wrapper
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// QuerySharded reads a partition written with WithWriteSharding: it queries all
+// of its shards concurrently and returns their items merged, in no particular
+// order. The items carry their shard suffixed partition key, as stored.
+func (ddb *DDBTable) QuerySharded(ctx context.Context, partitionKeyValue string, opts ...QueryOption) ([]map[string]interface{}, error) {
+	if ddb.writeShards == 0 {
+		return nil, fmt.Errorf("table %q has no write sharding configured, use WithWriteSharding", ddb.name)
+	}
+	options := newQueryOptions(opts)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	results := make([][]map[string]interface{}, ddb.writeShards)
+	errs := make([]error, ddb.writeShards)
+
+	for shard := 0; shard < ddb.writeShards; shard++ {
+		wg.Add(1)
+		go func(shard int) {
+			defer wg.Done()
+
+			input, err := ddb.partitionQueryInput(shardedKey(partitionKeyValue, shard), options)
+			if err == nil {
+				results[shard], err = ddb.queryAll(ctx, input)
+			}
+			if err != nil {
+				errs[shard] = err
+				cancel()
+			}
+		}(shard)
+	}
+	wg.Wait()
+
+	var merged []map[string]interface{}
+	for shard := range results {
+		if errs[shard] != nil {
+			return nil, errs[shard]
+		}
+		merged = append(merged, results[shard]...)
+	}
+
+	return merged, nil
+}
+
+////////////////////////
+// Internal functions //
+////////////////////////
+
+// shardItem moves the item to a random shard of its partition by suffixing its
+// partition key, when write sharding is enabled.
+func (ddb *DDBTable) shardItem(item map[string]types.AttributeValue) error {
+	if ddb.writeShards == 0 {
+		return nil
+	}
+
+	pk, ok := item[ddb.partitionKeyName].(*types.AttributeValueMemberS)
+	if !ok {
+		return fmt.Errorf("%w: write sharding needs a string partition key %q", ErrKeyTypeMismatch, ddb.partitionKeyName)
+	}
+	item[ddb.partitionKeyName] = &types.AttributeValueMemberS{Value: shardedKey(pk.Value, rand.IntN(ddb.writeShards))}
+
+	return nil
+}
+
+// checkUnsharded rejects the conditional writes and the item accesses by
+// partition key on a sharded table: a condition would be checked against a
+// random shard of the item only, and the unsuffixed key matches no shard.
+func (ddb *DDBTable) checkUnsharded(op string) error {
+	if ddb.writeShards > 0 {
+		return fmt.Errorf("%s is not supported on table %q with write sharding", op, ddb.name)
+	}
+	return nil
+}
+
+func shardedKey(partitionKeyValue string, shard int) string {
+	return fmt.Sprintf("%s#%d", partitionKeyValue, shard)
+}
@@ -0,0 +1,56 @@
+package go_dynamodb_wrapper
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestShardedTableRejectsKeyedAccess(t *testing.T) {
+	ddb, fake := newFakeTable(t, WithWriteSharding(4))
+	ctx := context.Background()
+
+	ops := map[string]func() error{
+		"ReadItem": func() error {
+			_, err := ddb.ReadItem("a")
+			return err
+		},
+		"UpdateItem": func() error {
+			return ddb.UpdateItem("a", map[string]interface{}{"v": 1})
+		},
+		"DeleteItem": func() error {
+			return ddb.DeleteItem("a")
+		},
+		"conditional TransactPut": func() error {
+			return ddb.TransactWriteBatch(ctx, []TransactOp{
+				TransactPut(map[string]interface{}{"id": "a"}).If("attribute_not_exists(id)", nil, nil),
+			})
+		},
+		"TransactUpdate": func() error {
+			return ddb.TransactWriteBatch(ctx, []TransactOp{
+				TransactUpdate("a", "SET v = :v", nil, map[string]interface{}{":v": 1}),
+			})
+		},
+		"TransactDelete": func() error {
+			return ddb.TransactWriteBatch(ctx, []TransactOp{TransactDelete("a")})
+		},
+		"TransactConditionCheck": func() error {
+			return ddb.TransactWriteBatch(ctx, []TransactOp{
+				TransactConditionCheck("a", "attribute_exists(id)", nil, nil),
+			})
+		},
+	}
+
+	for name, op := range ops {
+		if err := op(); err == nil || !strings.Contains(err.Error(), "write sharding") {
+			t.Errorf("%s on a sharded table: got error %v, want the write sharding error", name, err)
+		}
+	}
+	if len(fake.requests) != 0 {
+		t.Errorf("requests were sent: %v", fake.requests)
+	}
+
+	if err := ddb.WriteItem(map[string]interface{}{"id": "a"}); err != nil {
+		t.Errorf("WriteItem: %v", err)
+	}
+}
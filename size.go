@@ -0,0 +1,82 @@
+package go_dynamodb_wrapper
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	writeCapacityUnitSize = 1024
+	maxItemSize           = 400 * 1024
+)
+
+// EstimateWriteCapacity approximates the write capacity units writing items one
+// by one would consume: each item costs one unit per started KB of its size.
+// Transactional writes cost twice as much. Items MarshalMap rejects, and which
+// could not be written anyway, are not counted.
+func EstimateWriteCapacity(items []map[string]interface{}) float64 {
+	total := 0.0
+	for _, item := range items {
+		dynamodbItem, err := MarshalMap(item)
+		if err != nil {
+			continue
+		}
+		total += float64(max(1, (itemSize(dynamodbItem)+writeCapacityUnitSize-1)/writeCapacityUnitSize))
+	}
+	return total
+}
+
+////////////////////////
+// Internal functions //
+////////////////////////
+
+// itemSize approximates the size DynamoDB accounts for an item: the lengths of
+// the attribute names plus the sizes of their values. Numbers are counted by
+// their string length, which slightly overestimates them.
+func itemSize(item map[string]types.AttributeValue) int {
+	size := 0
+	for name, av := range item {
+		size += len(name) + attributeValueSize(av)
+	}
+	return size
+}
+
+func attributeValueSize(av types.AttributeValue) int {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberS:
+		return len(v.Value)
+	case *types.AttributeValueMemberN:
+		return len(v.Value)
+	case *types.AttributeValueMemberB:
+		return len(v.Value)
+	case *types.AttributeValueMemberBOOL, *types.AttributeValueMemberNULL:
+		return 1
+	case *types.AttributeValueMemberSS:
+		size := 0
+		for _, s := range v.Value {
+			size += len(s)
+		}
+		return size
+	case *types.AttributeValueMemberNS:
+		size := 0
+		for _, n := range v.Value {
+			size += len(n)
+		}
+		return size
+	case *types.AttributeValueMemberBS:
+		size := 0
+		for _, b := range v.Value {
+			size += len(b)
+		}
+		return size
+	case *types.AttributeValueMemberL:
+		size := 3
+		for _, item := range v.Value {
+			size += 1 + attributeValueSize(item)
+		}
+		return size
+	case *types.AttributeValueMemberM:
+		return 3 + len(v.Value) + itemSize(v.Value)
+	default:
+		return 0
+	}
+}
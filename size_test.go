@@ -0,0 +1,43 @@
+package go_dynamodb_wrapper
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestPrepareItemSizeLimit(t *testing.T) {
+	ddb := &DDBTable{name: "test", partitionKeyName: "id", partitionKeyType: types.ScalarAttributeTypeS}
+
+	// "id" and its value take 3 bytes, the payload attribute the rest.
+	item := func(payloadName string, payloadSize int) map[string]types.AttributeValue {
+		return map[string]types.AttributeValue{
+			"id":        &types.AttributeValueMemberS{Value: "k"},
+			payloadName: &types.AttributeValueMemberS{Value: strings.Repeat("x", payloadSize)},
+		}
+	}
+	fitting := maxItemSize - 3 - len("payload")
+
+	tests := []struct {
+		name     string
+		item     map[string]types.AttributeValue
+		tooLarge bool
+	}{
+		{"at the limit", item("payload", fitting), false},
+		{"one value byte over", item("payload", fitting+1), true},
+		{"one name byte over", item("payload_", fitting), true},
+		{"name bytes only", item(strings.Repeat("n", maxItemSize), 0), true},
+	}
+
+	for _, tt := range tests {
+		err := ddb.prepareItem(tt.item)
+		if tt.tooLarge != errors.Is(err, ErrItemTooLarge) {
+			t.Errorf("%s: itemSize %d, prepareItem = %v", tt.name, itemSize(tt.item), err)
+		}
+		if !tt.tooLarge && err != nil {
+			t.Errorf("%s: prepareItem = %v", tt.name, err)
+		}
+	}
+}
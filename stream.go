@@ -0,0 +1,202 @@
+package go_dynamodb_wrapper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams"
+	streamstypes "github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+)
+
+const streamPollInterval = time.Second
+
+// StreamRecord is a change delivered by ConsumeStream. SequenceNumber together
+// with ShardID is what a consumer should checkpoint, see WithStreamCheckpoints.
+type StreamRecord struct {
+	ShardID                     string
+	EventID                     string
+	EventName                   streamstypes.OperationType // INSERT, MODIFY or REMOVE
+	SequenceNumber              string
+	ApproximateCreationDateTime time.Time
+	Keys                        map[string]interface{}
+	NewImage                    map[string]interface{}
+	OldImage                    map[string]interface{}
+}
+
+// ConsumeStream reads the table's DynamoDB stream and calls handler for every
+// record, until ctx is cancelled or handler returns an error. Each shard is
+// polled in its own goroutine while handler calls are serialized; records are
+// ordered within a shard only. Images are converted with UnmarshalMap.
+// Shards are listed once at start, so a long running consumer should be
+// restarted from its checkpoints from time to time to pick up new shards.
+func (ddb *DDBTable) ConsumeStream(ctx context.Context, handler func(StreamRecord) error, opts ...StreamOption) error {
+	options := newStreamOptions(opts)
+
+	table, err := ddb.describeTable(ctx)
+	if err != nil {
+		return err
+	}
+	if table.LatestStreamArn == nil {
+		return fmt.Errorf("table %q has no stream enabled", ddb.name)
+	}
+
+	client := dynamodbstreams.NewFromConfig(ddb.awsConfig, func(o *dynamodbstreams.Options) {
+		if ddb.endpoint != "" {
+			o.BaseEndpoint = aws.String(ddb.endpoint)
+		}
+	})
+
+	shards, err := ddb.streamShards(ctx, client, table.LatestStreamArn)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		errOnce  sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+	serialized := func(record StreamRecord) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return handler(record)
+	}
+
+	for _, shard := range shards {
+		wg.Add(1)
+		go func(shardID string) {
+			defer wg.Done()
+			if err := ddb.consumeShard(ctx, client, table.LatestStreamArn, shardID, options, serialized); err != nil && !errors.Is(err, context.Canceled) {
+				fail(err)
+			}
+		}(aws.ToString(shard.ShardId))
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+////////////////////////
+// Internal functions //
+////////////////////////
+
+func (ddb *DDBTable) streamShards(ctx context.Context, client *dynamodbstreams.Client, streamArn *string) ([]streamstypes.Shard, error) {
+	var shards []streamstypes.Shard
+	input := &dynamodbstreams.DescribeStreamInput{StreamArn: streamArn}
+
+	for {
+		result, err := client.DescribeStream(ctx, input)
+		if err != nil {
+			return nil, ddb.wrapError("DescribeStream", err)
+		}
+		shards = append(shards, result.StreamDescription.Shards...)
+
+		if result.StreamDescription.LastEvaluatedShardId == nil {
+			return shards, nil
+		}
+		input.ExclusiveStartShardId = result.StreamDescription.LastEvaluatedShardId
+	}
+}
+
+func (ddb *DDBTable) consumeShard(ctx context.Context, client *dynamodbstreams.Client, streamArn *string, shardID string, options *streamOptions, handler func(StreamRecord) error) error {
+	iteratorInput := &dynamodbstreams.GetShardIteratorInput{
+		StreamArn:         streamArn,
+		ShardId:           aws.String(shardID),
+		ShardIteratorType: options.iteratorType,
+	}
+	if sequenceNumber, ok := options.checkpoints[shardID]; ok {
+		iteratorInput.ShardIteratorType = streamstypes.ShardIteratorTypeAfterSequenceNumber
+		iteratorInput.SequenceNumber = aws.String(sequenceNumber)
+	}
+
+	iterator, err := client.GetShardIterator(ctx, iteratorInput)
+	if err != nil {
+		return ddb.wrapError("GetShardIterator", fmt.Errorf("shard %s: %w", shardID, err))
+	}
+	shardIterator := iterator.ShardIterator
+
+	// A nil iterator means the shard has been closed and fully read
+	for shardIterator != nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		result, err := client.GetRecords(ctx, &dynamodbstreams.GetRecordsInput{ShardIterator: shardIterator})
+		if err != nil {
+			return ddb.wrapError("GetRecords", fmt.Errorf("shard %s: %w", shardID, err))
+		}
+
+		for _, r := range result.Records {
+			record, err := convertStreamRecord(shardID, r)
+			if err != nil {
+				return err
+			}
+			if err := handler(record); err != nil {
+				return err
+			}
+		}
+
+		shardIterator = result.NextShardIterator
+		if len(result.Records) == 0 && shardIterator != nil {
+			if err := sleepWithContext(ctx, streamPollInterval); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func convertStreamRecord(shardID string, r streamstypes.Record) (StreamRecord, error) {
+	record := StreamRecord{
+		ShardID:   shardID,
+		EventID:   aws.ToString(r.EventID),
+		EventName: r.EventName,
+	}
+	if r.Dynamodb == nil {
+		return record, nil
+	}
+
+	record.SequenceNumber = aws.ToString(r.Dynamodb.SequenceNumber)
+	record.ApproximateCreationDateTime = aws.ToTime(r.Dynamodb.ApproximateCreationDateTime)
+
+	for _, image := range []struct {
+		from map[string]streamstypes.AttributeValue
+		to   *map[string]interface{}
+	}{
+		{r.Dynamodb.Keys, &record.Keys},
+		{r.Dynamodb.NewImage, &record.NewImage},
+		{r.Dynamodb.OldImage, &record.OldImage},
+	} {
+		if image.from == nil {
+			continue
+		}
+		converted, err := attributevalue.FromDynamoDBStreamsMap(image.from)
+		if err != nil {
+			return StreamRecord{}, err
+		}
+		if *image.to, err = UnmarshalMap(converted); err != nil {
+			return StreamRecord{}, err
+		}
+	}
+
+	return record, nil
+}
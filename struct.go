@@ -0,0 +1,59 @@
+package go_dynamodb_wrapper
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+// WriteStruct marshals v with the SDK's attributevalue encoder (honouring the
+// `dynamodbav` tags, or whatever WithEncoderOptions configured) and writes it.
+func (ddb *DDBTable) WriteStruct(ctx context.Context, v interface{}) error {
+	item, err := attributevalue.MarshalMapWithOptions(v, ddb.encoderOptions...)
+	if err != nil {
+		return fmt.Errorf("failed to marshal item: %w", err)
+	}
+	if err := ddb.prepareItem(item); err != nil {
+		return err
+	}
+
+	input := &dynamodb.PutItemInput{
+		TableName: aws.String(ddb.name),
+		Item:      item,
+	}
+
+	_, err = ddb.putItem(ctx, input)
+	return err
+}
+
+// ReadStruct reads the item with the given partition key and unmarshals it into
+// out, which must be a non-nil pointer.
+func (ddb *DDBTable) ReadStruct(ctx context.Context, partitionKeyValue string, out interface{}) error {
+	key, err := ddb.key(partitionKeyValue)
+	if err != nil {
+		return err
+	}
+
+	input := &dynamodb.GetItemInput{
+		TableName:      aws.String(ddb.name),
+		Key:            key,
+		ConsistentRead: ddb.consistentRead(nil),
+	}
+
+	result, err := ddb.getItem(ctx, input)
+	if err != nil {
+		return err
+	}
+	if result.Item == nil {
+		return ErrItemNotFound
+	}
+
+	if err := attributevalue.UnmarshalMapWithOptions(result.Item, out, ddb.decoderOptions...); err != nil {
+		return fmt.Errorf("failed to unmarshal item: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,219 @@
+package go_dynamodb_wrapper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const tableActiveTimeout = 5 * time.Minute
+
+// Ping checks that the table is reachable with the current credentials by
+// describing it, which makes it suitable for readiness probes.
+func (ddb *DDBTable) Ping(ctx context.Context) error {
+	_, err := ddb.describeTable(ctx)
+	return err
+}
+
+// TableInfo describes the table as reported by DescribeTable.
+type TableInfo struct {
+	Name             string
+	ARN              string
+	ID               string
+	Status           types.TableStatus
+	CreationDateTime time.Time
+}
+
+// Info describes the table, e.g. to tag logs and metrics with its ARN.
+func (ddb *DDBTable) Info(ctx context.Context) (TableInfo, error) {
+	description, err := ddb.describeTable(ctx)
+	if err != nil {
+		return TableInfo{}, err
+	}
+
+	return TableInfo{
+		Name:             aws.ToString(description.TableName),
+		ARN:              aws.ToString(description.TableArn),
+		ID:               aws.ToString(description.TableId),
+		Status:           description.TableStatus,
+		CreationDateTime: aws.ToTime(description.CreationDateTime),
+	}, nil
+}
+
+// ApproximateItemCount returns the number of items DescribeTable reports. It
+// costs no read capacity but DynamoDB only refreshes it about every six hours,
+// so it lags behind recent writes.
+func (ddb *DDBTable) ApproximateItemCount(ctx context.Context) (int64, error) {
+	description, err := ddb.describeTable(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return aws.ToInt64(description.ItemCount), nil
+}
+
+// CreateTable creates the table with on-demand billing, keyed by the configured
+// partition key (of the configured type) and, when set, a string sort key. It
+// waits for the table to become active. A table that already exists is not an
+// error.
+func (ddb *DDBTable) CreateTable(ctx context.Context, opts ...CreateTableOption) error {
+	options := newCreateTableOptions(opts)
+
+	attributeDefinitions, keySchema := ddb.keySchema()
+	input := &dynamodb.CreateTableInput{
+		TableName:            aws.String(ddb.name),
+		BillingMode:          types.BillingModePayPerRequest,
+		AttributeDefinitions: attributeDefinitions,
+		KeySchema:            keySchema,
+	}
+	for _, apply := range options.input {
+		apply(input)
+	}
+
+	_, err := ddb.client.CreateTable(ctx, input)
+	var inUse *types.ResourceInUseException
+	if err != nil && !errors.As(err, &inUse) {
+		return ddb.wrapError("CreateTable", err)
+	}
+
+	waiter := dynamodb.NewTableExistsWaiter(ddb.client)
+	if err := waiter.Wait(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(ddb.name)}, tableActiveTimeout); err != nil {
+		return ddb.wrapError("CreateTable", fmt.Errorf("table did not become active: %w", err))
+	}
+
+	return nil
+}
+
+// ImportFromS3 creates the table from the data under s3Prefix in s3Bucket, with
+// the same key schema and billing mode as CreateTable. The table must not exist
+// yet; it stays in CREATING state until the import has completed, which for
+// large imports takes a while.
+func (ddb *DDBTable) ImportFromS3(ctx context.Context, s3Bucket, s3Prefix string, format types.InputFormat) error {
+	attributeDefinitions, keySchema := ddb.keySchema()
+	source := &types.S3BucketSource{S3Bucket: aws.String(s3Bucket)}
+	if s3Prefix != "" {
+		source.S3KeyPrefix = aws.String(s3Prefix)
+	}
+
+	_, err := ddb.importTable(ctx, &dynamodb.ImportTableInput{
+		S3BucketSource: source,
+		InputFormat:    format,
+		TableCreationParameters: &types.TableCreationParameters{
+			TableName:            aws.String(ddb.name),
+			BillingMode:          types.BillingModePayPerRequest,
+			AttributeDefinitions: attributeDefinitions,
+			KeySchema:            keySchema,
+		},
+	})
+	return err
+}
+
+// EnableContributorInsights turns on CloudWatch Contributor Insights for the
+// table, or for one of its indexes with WithInsightsIndex, to find the most
+// accessed and throttled keys.
+func (ddb *DDBTable) EnableContributorInsights(ctx context.Context, opts ...ContributorInsightsOption) error {
+	return ddb.setContributorInsights(ctx, types.ContributorInsightsActionEnable, newContributorInsightsOptions(opts))
+}
+
+// DisableContributorInsights turns CloudWatch Contributor Insights off again.
+func (ddb *DDBTable) DisableContributorInsights(ctx context.Context, opts ...ContributorInsightsOption) error {
+	return ddb.setContributorInsights(ctx, types.ContributorInsightsActionDisable, newContributorInsightsOptions(opts))
+}
+
+// EncryptionConfig describes how a table is encrypted at rest. All fields are
+// empty when the table uses the default AWS owned key.
+type EncryptionConfig struct {
+	Status    types.SSEStatus
+	Type      types.SSEType
+	KMSKeyARN string
+}
+
+// UpdateEncryption switches the table's encryption at rest to the given customer
+// managed KMS key. The change is applied asynchronously, Encryption reports its
+// progress.
+func (ddb *DDBTable) UpdateEncryption(ctx context.Context, kmsKeyID string) error {
+	_, err := ddb.updateTable(ctx, &dynamodb.UpdateTableInput{
+		TableName:        aws.String(ddb.name),
+		SSESpecification: kmsSSESpecification(kmsKeyID),
+	})
+	return err
+}
+
+// Encryption reads back the table's current encryption at rest configuration,
+// e.g. to detect drift from the expected KMS key.
+func (ddb *DDBTable) Encryption(ctx context.Context) (EncryptionConfig, error) {
+	description, err := ddb.describeTable(ctx)
+	if err != nil {
+		return EncryptionConfig{}, err
+	}
+	if description.SSEDescription == nil {
+		return EncryptionConfig{}, nil
+	}
+
+	return EncryptionConfig{
+		Status:    description.SSEDescription.Status,
+		Type:      description.SSEDescription.SSEType,
+		KMSKeyARN: aws.ToString(description.SSEDescription.KMSMasterKeyArn),
+	}, nil
+}
+
+////////////////////////
+// Internal functions //
+////////////////////////
+
+// keySchema describes the table's keys: the partition key of the configured type
+// and, when set, a string sort key.
+func (ddb *DDBTable) keySchema() ([]types.AttributeDefinition, []types.KeySchemaElement) {
+	attributeDefinitions := []types.AttributeDefinition{
+		{AttributeName: aws.String(ddb.partitionKeyName), AttributeType: ddb.partitionKeyType},
+	}
+	keySchema := []types.KeySchemaElement{
+		{AttributeName: aws.String(ddb.partitionKeyName), KeyType: types.KeyTypeHash},
+	}
+	if ddb.sortKeyName != "" {
+		attributeDefinitions = append(attributeDefinitions, types.AttributeDefinition{AttributeName: aws.String(ddb.sortKeyName), AttributeType: types.ScalarAttributeTypeS})
+		keySchema = append(keySchema, types.KeySchemaElement{AttributeName: aws.String(ddb.sortKeyName), KeyType: types.KeyTypeRange})
+	}
+
+	return attributeDefinitions, keySchema
+}
+
+func (ddb *DDBTable) setContributorInsights(ctx context.Context, action types.ContributorInsightsAction, options *contributorInsightsOptions) error {
+	input := &dynamodb.UpdateContributorInsightsInput{
+		TableName:                 aws.String(ddb.name),
+		ContributorInsightsAction: action,
+	}
+	if options.indexName != "" {
+		input.IndexName = aws.String(options.indexName)
+	}
+
+	_, err := ddb.updateContributorInsights(ctx, input)
+	return err
+}
+
+// createTableIfEnabled creates the missing table when WithAutoCreateTable is set,
+// it reports whether the failed write should be retried.
+func (ddb *DDBTable) createTableIfEnabled(ctx context.Context, err error) (bool, error) {
+	if !ddb.autoCreateTable || !errors.Is(err, ErrTableNotFound) {
+		return false, err
+	}
+
+	if err := ddb.CreateTable(ctx); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func kmsSSESpecification(kmsKeyID string) *types.SSESpecification {
+	return &types.SSESpecification{
+		Enabled:        aws.Bool(true),
+		SSEType:        types.SSETypeKms,
+		KMSMasterKeyId: aws.String(kmsKeyID),
+	}
+}
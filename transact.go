@@ -0,0 +1,199 @@
+package go_dynamodb_wrapper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const transactMaxOps = 100
+
+// TransactOp is a single op of a TransactWriteBatch, built with TransactPut,
+// TransactUpdate, TransactDelete or TransactConditionCheck. Writes can
+// optionally be guarded with If.
+type TransactOp struct {
+	item              map[string]interface{}
+	partitionKeyValue string
+	updateExpression  string
+	conditionCheck    bool
+	condition         string
+	names             map[string]string
+	values            map[string]interface{}
+}
+
+// TransactPut writes item, replacing any existing item with the same key.
+func TransactPut(item map[string]interface{}) TransactOp {
+	return TransactOp{item: item}
+}
+
+// TransactUpdate applies updateExpression to the item with the given partition
+// key. names and values fill the expression's placeholders and may be nil.
+func TransactUpdate(partitionKeyValue, updateExpression string, names map[string]string, values map[string]interface{}) TransactOp {
+	return TransactOp{
+		partitionKeyValue: partitionKeyValue,
+		updateExpression:  updateExpression,
+		names:             names,
+		values:            values,
+	}
+}
+
+// TransactDelete deletes the item with the given partition key.
+func TransactDelete(partitionKeyValue string) TransactOp {
+	return TransactOp{partitionKeyValue: partitionKeyValue}
+}
+
+// TransactConditionCheck asserts that condition holds for the item with the
+// given partition key, without modifying it: the transaction fails otherwise.
+// names and values fill the condition's placeholders and may be nil.
+func TransactConditionCheck(partitionKeyValue, condition string, names map[string]string, values map[string]interface{}) TransactOp {
+	return TransactOp{
+		partitionKeyValue: partitionKeyValue,
+		conditionCheck:    true,
+		condition:         condition,
+		names:             names,
+		values:            values,
+	}
+}
+
+// If makes the op, and with it the whole transaction, fail unless condition
+// holds. Its placeholders are filled from names and values, which are merged
+// with those of the update expression.
+func (op TransactOp) If(condition string, names map[string]string, values map[string]interface{}) TransactOp {
+	op.condition = condition
+	op.names = mergeMaps(op.names, names)
+	op.values = mergeMaps(op.values, values)
+	return op
+}
+
+// TransactWriteBatch applies up to 100 ops atomically: either all of them
+// succeed or none does. When the transaction is canceled, for instance because
+// a condition failed, the *TransactionCanceledError lists the reason of each op.
+func (ddb *DDBTable) TransactWriteBatch(ctx context.Context, ops []TransactOp) error {
+	if len(ops) == 0 {
+		return nil
+	}
+	if len(ops) > transactMaxOps {
+		return fmt.Errorf("transaction has %d operations, at most %d are allowed", len(ops), transactMaxOps)
+	}
+
+	items := make([]types.TransactWriteItem, 0, len(ops))
+	for i, op := range ops {
+		item, err := ddb.transactWriteItem(op)
+		if err != nil {
+			return fmt.Errorf("operation %d: %w", i, err)
+		}
+		items = append(items, item)
+	}
+
+	_, err := ddb.transactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+	var canceled *types.TransactionCanceledException
+	if errors.As(err, &canceled) {
+		reasons := make([]CancellationReason, len(canceled.CancellationReasons))
+		for i, r := range canceled.CancellationReasons {
+			reasons[i] = CancellationReason{Code: aws.ToString(r.Code), Message: aws.ToString(r.Message)}
+		}
+		return &TransactionCanceledError{Reasons: reasons, Err: err}
+	}
+
+	return err
+}
+
+////////////////////////
+// Internal functions //
+////////////////////////
+
+func (ddb *DDBTable) transactWriteItem(op TransactOp) (types.TransactWriteItem, error) {
+	var condition *string
+	if op.condition != "" {
+		condition = aws.String(op.condition)
+	}
+	var names map[string]string
+	if len(op.names) > 0 {
+		names = op.names
+	}
+	var values map[string]types.AttributeValue
+	if len(op.values) > 0 {
+		var err error
+		values, err = ddb.marshalMap(op.values)
+		if err != nil {
+			return types.TransactWriteItem{}, err
+		}
+	}
+
+	if op.item != nil {
+		if condition != nil {
+			if err := ddb.checkUnsharded("TransactPut with a condition"); err != nil {
+				return types.TransactWriteItem{}, err
+			}
+		}
+		item, err := ddb.marshalMap(op.item)
+		if err != nil {
+			return types.TransactWriteItem{}, err
+		}
+		if err := ddb.prepareItem(item); err != nil {
+			return types.TransactWriteItem{}, err
+		}
+		return types.TransactWriteItem{Put: &types.Put{
+			TableName:                 aws.String(ddb.name),
+			Item:                      item,
+			ConditionExpression:       condition,
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+		}}, nil
+	}
+
+	key, err := ddb.key(op.partitionKeyValue)
+	if err != nil {
+		return types.TransactWriteItem{}, err
+	}
+
+	if op.conditionCheck {
+		return types.TransactWriteItem{ConditionCheck: &types.ConditionCheck{
+			TableName:                 aws.String(ddb.name),
+			Key:                       key,
+			ConditionExpression:       condition,
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+		}}, nil
+	}
+
+	if op.updateExpression != "" {
+		updateExpression, names, values := ddb.stampUpdateExpression(op.updateExpression, names, values)
+		return types.TransactWriteItem{Update: &types.Update{
+			TableName:                 aws.String(ddb.name),
+			Key:                       key,
+			UpdateExpression:          updateExpression,
+			ConditionExpression:       condition,
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+		}}, nil
+	}
+
+	return types.TransactWriteItem{Delete: &types.Delete{
+		TableName:                 aws.String(ddb.name),
+		Key:                       key,
+		ConditionExpression:       condition,
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	}}, nil
+}
+
+// mergeMaps returns the union of a and b without modifying either.
+func mergeMaps[V any](a, b map[string]V) map[string]V {
+	if len(b) == 0 {
+		return a
+	}
+
+	merged := make(map[string]V, len(a)+len(b))
+	for k, v := range a {
+		merged[k] = v
+	}
+	for k, v := range b {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,27 @@
+package go_dynamodb_wrapper
+
+import (
+	"fmt"
+	"time"
+)
+
+// maxTTLHorizon bounds how far in the future a TTL may lie. Anything beyond it is
+// almost certainly an epoch in milliseconds (which is ~1000x too big and would
+// never expire) rather than the epoch seconds DynamoDB expects.
+const maxTTLHorizon = 100 * 365 * 24 * time.Hour
+
+// SetTTLAt stores t in epoch seconds in the TTL attribute of item.
+func SetTTLAt(item map[string]interface{}, attributeName string, t time.Time) error {
+	return SetTTL(item, attributeName, t.Unix())
+}
+
+// SetTTL stores an epoch in seconds in the TTL attribute of item, rejecting
+// values that are absurdly far in the future, such as epochs in milliseconds.
+func SetTTL(item map[string]interface{}, attributeName string, epochSeconds int64) error {
+	if epochSeconds > time.Now().Add(maxTTLHorizon).Unix() {
+		return fmt.Errorf("%w: %d is too far in the future, is it in milliseconds instead of seconds?", ErrInvalidTTL, epochSeconds)
+	}
+
+	item[attributeName] = epochSeconds
+	return nil
+}